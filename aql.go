@@ -0,0 +1,145 @@
+package arangom
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// aqlDirectivePrefix is the prefix of the directive comments of the AQL
+	// migration files.
+	aqlDirectivePrefix = "+arangom"
+	// aqlStatementBegin is the directive opening a statement block.
+	aqlStatementBegin = "StatementBegin"
+	// aqlStatementEnd is the directive closing a statement block.
+	aqlStatementEnd = "StatementEnd"
+	// aqlIDDirective is the directive setting the ID of the migration.
+	aqlIDDirective = "id:"
+)
+
+// ErrInvalidAQLMigration is returned when an AQL migration file is malformed.
+var ErrInvalidAQLMigration = fmt.Errorf("invalid AQL migration")
+
+// parseAQLMigration parses an AQL migration file. The directives of the file
+// are comments starting with "+arangom", using either the AQL "//" or the SQL
+// "--" comment style:
+//
+//	// +arangom id: 1677564650
+//	// +arangom StatementBegin
+//	FOR u IN users UPDATE u WITH { active: true } IN users
+//	// +arangom StatementEnd
+//
+// The optional "id" directive must precede the queries. Without statement
+// blocks, the whole file is a single query. With statement blocks, every
+// block is a query and only comments are allowed outside the blocks. Every
+// query becomes an executeAQL operation.
+func parseAQLMigration(name string, b []byte) (*Migration, error) {
+	m := new(Migration)
+
+	var (
+		statement strings.Builder
+		inBlock   bool
+		hasBlocks bool
+		outside   []int // lines of the queries outside statement blocks
+	)
+
+	flush := func() {
+		if query := strings.TrimSpace(statement.String()); query != "" {
+			m.Operations = append(m.Operations, &Operation{
+				Kind:    OperationKindAQLExecute,
+				Options: map[string]any{"query": query},
+			})
+		}
+
+		statement.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		directive, ok := aqlDirective(text)
+		if !ok {
+			trimmed := strings.TrimSpace(text)
+			if !inBlock && trimmed != "" && !strings.HasPrefix(trimmed, "//") {
+				outside = append(outside, line)
+			}
+
+			statement.WriteString(text)
+			statement.WriteByte('\n')
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(directive, aqlIDDirective):
+			if inBlock || len(m.Operations) > 0 || len(outside) > 0 {
+				return nil, fmt.Errorf("%w: %s:%d: the id must precede the queries", ErrInvalidAQLMigration, name, line)
+			}
+
+			id, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(directive, aqlIDDirective)))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s:%d: invalid id: %s", ErrInvalidAQLMigration, name, line, err.Error())
+			}
+
+			m.ID = id
+		case directive == aqlStatementBegin:
+			if inBlock {
+				return nil, fmt.Errorf("%w: %s:%d: nested %s", ErrInvalidAQLMigration, name, line, aqlStatementBegin)
+			}
+
+			statement.Reset()
+			inBlock, hasBlocks = true, true
+		case directive == aqlStatementEnd:
+			if !inBlock {
+				return nil, fmt.Errorf("%w: %s:%d: %s without %s", ErrInvalidAQLMigration, name, line, aqlStatementEnd, aqlStatementBegin)
+			}
+
+			flush()
+			inBlock = false
+		default:
+			return nil, fmt.Errorf("%w: %s:%d: unknown directive: %s", ErrInvalidAQLMigration, name, line, directive)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("%w: %s: unterminated %s", ErrInvalidAQLMigration, name, aqlStatementBegin)
+	}
+
+	if hasBlocks && len(outside) > 0 {
+		return nil, fmt.Errorf("%w: %s:%d: query outside of a statement block", ErrInvalidAQLMigration, name, outside[0])
+	}
+
+	if !hasBlocks {
+		flush()
+	}
+
+	return m, nil
+}
+
+// aqlDirective returns the directive of the line, if the line is a directive
+// comment.
+func aqlDirective(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+
+	comment, ok := strings.CutPrefix(line, "//")
+	if !ok {
+		if comment, ok = strings.CutPrefix(line, "--"); !ok {
+			return "", false
+		}
+	}
+
+	directive, ok := strings.CutPrefix(strings.TrimSpace(comment), aqlDirectivePrefix)
+	if !ok {
+		return "", false
+	}
+
+	return strings.TrimSpace(directive), true
+}
@@ -0,0 +1,138 @@
+package arangom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseAQLMigration(t *testing.T) {
+	aqlOperation := func(query string) *Operation {
+		return &Operation{
+			Kind:    OperationKindAQLExecute,
+			Options: map[string]any{"query": query},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    *Migration
+		wantErr error
+	}{
+		{
+			name: "parse single query",
+			data: "FOR u IN users\n  UPDATE u WITH { active: true } IN users\n",
+			want: &Migration{
+				Operations: []*Operation{
+					aqlOperation("FOR u IN users\n  UPDATE u WITH { active: true } IN users"),
+				},
+			},
+		},
+		{
+			name: "parse id header",
+			data: "// Activate every user.\n// +arangom id: 123\n\nFOR u IN users UPDATE u WITH { active: true } IN users\n",
+			want: &Migration{
+				ID: 123,
+				Operations: []*Operation{
+					aqlOperation("// Activate every user.\n\nFOR u IN users UPDATE u WITH { active: true } IN users"),
+				},
+			},
+		},
+		{
+			name: "parse statement blocks",
+			data: "-- +arangom id: 123\n" +
+				"-- +arangom StatementBegin\nFOR u IN users UPDATE u WITH { active: true } IN users\n-- +arangom StatementEnd\n" +
+				"\n// Posts of the active users.\n" +
+				"// +arangom StatementBegin\nFOR p IN posts UPDATE p WITH { visible: true } IN posts\n// +arangom StatementEnd\n",
+			want: &Migration{
+				ID: 123,
+				Operations: []*Operation{
+					aqlOperation("FOR u IN users UPDATE u WITH { active: true } IN users"),
+					aqlOperation("FOR p IN posts UPDATE p WITH { visible: true } IN posts"),
+				},
+			},
+		},
+		{
+			name: "parse empty file",
+			data: "// +arangom id: 123\n",
+			want: &Migration{
+				ID: 123,
+			},
+		},
+		{
+			name:    "parse id after query",
+			data:    "FOR u IN users RETURN u\n// +arangom id: 123\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+		{
+			name:    "parse invalid id",
+			data:    "// +arangom id: abc\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+		{
+			name:    "parse unknown directive",
+			data:    "// +arangom Down\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+		{
+			name:    "parse nested statement block",
+			data:    "// +arangom StatementBegin\n// +arangom StatementBegin\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+		{
+			name:    "parse statement end without begin",
+			data:    "// +arangom StatementEnd\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+		{
+			name:    "parse unterminated statement block",
+			data:    "// +arangom StatementBegin\nFOR u IN users RETURN u\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+		{
+			name:    "parse query outside of statement blocks",
+			data:    "// +arangom StatementBegin\nFOR u IN users RETURN u\n// +arangom StatementEnd\nFOR p IN posts RETURN p\n",
+			wantErr: ErrInvalidAQLMigration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseAQLMigration("migration.aql", []byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseAQLMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAQLMigration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAQLDirective(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   string
+		wantOk bool
+	}{
+		{line: "// +arangom StatementBegin", want: "StatementBegin", wantOk: true},
+		{line: "  --+arangom id: 123", want: "id: 123", wantOk: true},
+		{line: "// a comment", want: "", wantOk: false},
+		{line: "RETURN 1", want: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := aqlDirective(tt.line)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("aqlDirective() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -216,6 +216,47 @@ func (m *MockArangoDB) Transaction(ctx context.Context, action string, options *
 	return args.Get(0), args.Error(1)
 }
 
+type MockArangoCursor struct {
+	mock.Mock
+}
+
+func (m *MockArangoCursor) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockArangoCursor) HasMore() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockArangoCursor) ReadDocument(ctx context.Context, result any) (arangoDriver.DocumentMeta, error) {
+	args := m.Called(ctx, result)
+	convertToAny(args.Get(0), &result)
+	return args.Get(1).(arangoDriver.DocumentMeta), args.Error(2)
+}
+
+func (m *MockArangoCursor) RetryReadDocument(ctx context.Context, result any) (arangoDriver.DocumentMeta, error) {
+	args := m.Called(ctx, result)
+	convertToAny(args.Get(0), &result)
+	return args.Get(1).(arangoDriver.DocumentMeta), args.Error(2)
+}
+
+func (m *MockArangoCursor) Count() int64 {
+	args := m.Called()
+	return args.Get(0).(int64)
+}
+
+func (m *MockArangoCursor) Statistics() arangoDriver.QueryStatistics {
+	args := m.Called()
+	return args.Get(0).(arangoDriver.QueryStatistics)
+}
+
+func (m *MockArangoCursor) Extra() arangoDriver.QueryExtra {
+	args := m.Called()
+	return args.Get(0).(arangoDriver.QueryExtra)
+}
+
 type MockArangoCollection struct {
 	mock.Mock
 }
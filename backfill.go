@@ -0,0 +1,80 @@
+package arangom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultBackfillBatchSize is the number of documents the backfillAQL
+	// operation processes at once if the operation sets no batch size.
+	DefaultBackfillBatchSize = 1000
+)
+
+// ErrBackfillStalled is returned when the query of a backfillAQL operation
+// does not return keys following the last processed key, which would process
+// the same documents over and over.
+var ErrBackfillStalled = fmt.Errorf("backfill stalled")
+
+// resumableKinds are the operation kinds resuming from their checkpoint when
+// their migration is resumed.
+var resumableKinds = []OperationKind{OperationKindAQLBackfill}
+
+type progressCtxKey struct{}
+
+// operationProgress is the progress of a resumable operation. The checkpoint
+// is recorded by an earlier execution of the operation, or empty, while save
+// records a new checkpoint. The progress is logged by the logger.
+type operationProgress struct {
+	checkpoint string
+	save       func(checkpoint string) error
+	logger     Logger
+}
+
+// withOperationProgress returns a context with the given progress stored in
+// it.
+func withOperationProgress(ctx context.Context, progress *operationProgress) context.Context {
+	return context.WithValue(ctx, progressCtxKey{}, progress)
+}
+
+// operationProgressFromContext retrieves the progress from the context. If it
+// is not set, a progress starting from scratch, discarding the checkpoints and
+// logging with the default logger, is returned.
+func operationProgressFromContext(ctx context.Context) *operationProgress {
+	if progress, ok := ctx.Value(progressCtxKey{}).(*operationProgress); ok && progress != nil {
+		return progress
+	}
+
+	return &operationProgress{
+		save:   func(string) error { return nil },
+		logger: NewDefaultLogger(),
+	}
+}
+
+// backfillAQLOpts are the options of the backfillAQL operation.
+type backfillAQLOpts struct {
+	Query     string         `json:"query"`
+	BindVars  map[string]any `json:"bindVars"`
+	BatchSize int            `json:"batchSize"`
+}
+
+// validate returns an error if the batch size is invalid or the query does not
+// use the bind variables of the batch.
+func (o *backfillAQLOpts) validate() error {
+	if o.BatchSize < 0 {
+		return fmt.Errorf("batchSize must not be negative, got %d", o.BatchSize)
+	}
+
+	for _, name := range []string{"batch", "lastKey"} {
+		if !strings.Contains(o.Query, "@"+name) {
+			return fmt.Errorf("query must use the @%s bind variable", name)
+		}
+
+		if _, ok := o.BindVars[name]; ok {
+			return fmt.Errorf("bindVars must not set %s, it is set for every batch", name)
+		}
+	}
+
+	return nil
+}
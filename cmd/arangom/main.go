@@ -2,16 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	arangoDriver "github.com/arangodb/go-driver"
 	arangoHTTP "github.com/arangodb/go-driver/http"
-	"gopkg.in/yaml.v3"
 
 	"github.com/gabor-boros/arangom"
 )
@@ -19,6 +20,7 @@ import (
 const (
 	DefaultDatabaseEndpoints = "http://localhost:8529" // default database endpoints
 	DefaultMigrationDir      = "migrations"            // default migration directory
+	DefaultCommand           = "migrate"               // default command to run
 )
 
 var (
@@ -30,13 +32,48 @@ var (
 	collection       string // collection in which migrations are stored
 	createCollection bool   // create collection if it does not exist
 
-	migrationDir string // directory where migrations are stored
+	migrationDir   string  // directory where migrations are stored
+	migrationOrder string  // strategy of ordering the migrations
+	variables      varFlag // variables substituted in the migration files
+
+	dryRun              bool          // report what would be executed without changing the database
+	lockWait            time.Duration // time to wait for the migration lock
+	allowChecksumChange bool          // update the checksum of changed applied migrations
+	target              string        // last migration to execute
+	outOfOrder          string        // response to out of order migrations
 
 	version = "dev"                           // version of the binary
 	commit  = "dirty"                         // git commit hash
 	date    = time.Now().Format(time.RFC3339) // build date
 )
 
+// varFlag is the value of the repeatable -var flag.
+type varFlag map[string]string
+
+// String implements the flag.Value interface.
+func (v varFlag) String() string {
+	pairs := make([]string, 0, len(v))
+	for key, value := range v {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// Set implements the flag.Value interface.
+func (v varFlag) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("variable must be in key=value format: %s", pair)
+	}
+
+	v[key] = value
+
+	return nil
+}
+
 func init() {
 	flag.StringVar(&dbUsername, "username", "", "Database user")
 	flag.StringVar(&dbPassword, "password", "", "Database password")
@@ -44,23 +81,46 @@ func init() {
 	flag.StringVar(&dbEndpoints, "endpoints", DefaultDatabaseEndpoints, "Comma-separated list of database endpoints")
 
 	flag.StringVar(&migrationDir, "migration-dir", DefaultMigrationDir, "Migration directory")
+	flag.StringVar(&migrationOrder, "order", arangom.OrderByFilename.String(), "Order of the migrations (filename, id or version)")
+	variables = make(map[string]string)
+	flag.Var(variables, "var", "Variable substituted in the migration files in key=value format (repeatable)")
 	flag.StringVar(&collection, "collection", arangom.DefaultMigrationCollection, "Migration collection")
 	flag.BoolVar(&createCollection, "create-collection", true, "Create migration collection if it does not exist")
+	flag.BoolVar(&dryRun, "dry-run", false, "Report what would be executed without changing the database (migrate and status only)")
+	flag.DurationVar(&lockWait, "lock-wait", arangom.DefaultLockTimeout, "Time to wait for the migration lock held by an other run")
+	flag.StringVar(&target, "target", "", "Execute the pending migrations up to the given migration ID or name")
+	flag.StringVar(&outOfOrder, "out-of-order", arangom.OutOfOrderFail.String(), "Response to pending migrations sorting before the latest applied one (allow, warn or fail)")
+	flag.BoolVar(&allowChecksumChange, "allow-checksum-change", false, "Update the recorded checksum of applied migrations that changed instead of failing")
 
 	printVersion := flag.Bool("version", false, "Print version and exit")
 
+	flag.Usage = usage
 	flag.Parse()
 
 	if printVersion != nil && *printVersion {
 		fmt.Printf("arangom version %s, commit %s (%s)\n", version, commit, date)
 		os.Exit(0)
 	}
+}
 
-	if err := validateFlags(); err != nil {
-		panic(err)
-	}
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage: arangom [flags] [command] [command flags]\n\n")
+	_, _ = fmt.Fprintf(out, "Commands:\n")
+	_, _ = fmt.Fprintf(out, "  migrate\tExecute pending migrations (default)\n")
+	_, _ = fmt.Fprintf(out, "  retry\tResume failed migrations and execute pending migrations\n")
+	_, _ = fmt.Fprintf(out, "  rollback\tRoll back applied migrations\n")
+	_, _ = fmt.Fprintf(out, "  status\tShow the status of the migrations\n")
+	_, _ = fmt.Fprintf(out, "  repair\tUpdate the recorded checksum of applied migrations that changed\n")
+	_, _ = fmt.Fprintf(out, "  validate\tCheck the migration files without connecting to the database\n")
+	_, _ = fmt.Fprintf(out, "  schema\tPrint the JSON Schema of the migration files\n")
+	_, _ = fmt.Fprintf(out, "  force-unlock\tRemove the migration lock left by a crashed run\n\n")
+	_, _ = fmt.Fprintf(out, "Flags:\n")
+	flag.PrintDefaults()
 }
 
+// validateFlags validates the flags required to run the commands connecting to
+// the database.
 func validateFlags() error {
 	if dbUsername == "" {
 		return fmt.Errorf("database username is required")
@@ -115,7 +175,7 @@ func initDatabase() (arangoDriver.Database, arangoDriver.Connection, error) {
 		return nil, nil, err
 	}
 
-	if !collExists && createCollection {
+	if !collExists && createCollection && !dryRun {
 		if _, err := db.CreateCollection(context.Background(), collection, nil); err != nil {
 			return nil, nil, err
 		}
@@ -124,53 +184,200 @@ func initDatabase() (arangoDriver.Database, arangoDriver.Connection, error) {
 	return db, client.Connection(), nil
 }
 
-// loadMigration loads a single migration file.
-func loadMigration(path string) (*arangom.Migration, error) {
-	b, err := os.ReadFile(path)
+// runRollback rolls back the applied migrations as requested by the rollback
+// command flags.
+func runRollback(ctx context.Context, executor *arangom.Executor, args []string) error {
+	flags := flag.NewFlagSet("rollback", flag.ExitOnError)
+	steps := flags.Int("steps", 1, "Number of applied migrations to roll back")
+	target := flags.String("to", "", "Roll back every migration applied after the given migration ID or name")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *target != "" {
+		return executor.RollbackTo(ctx, *target)
+	}
+
+	return executor.Rollback(ctx, *steps)
+}
+
+// runStatus prints the status of the migrations in the format requested by the
+// status command flags.
+func runStatus(ctx context.Context, executor *arangom.Executor, args []string) error {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	format := flags.String("format", "table", "Output format (table or json)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown status format: %s", *format)
+	}
+
+	states, err := executor.Status(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	m := new(arangom.Migration)
-	if err := yaml.Unmarshal(b, m); err != nil {
-		return nil, err
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(states)
 	}
 
-	m.Path = path
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tSTATUS\tAPPLIED AT\tCHECKSUM\tNOTE")
 
-	return m, nil
-}
+	for _, state := range states {
+		appliedAt := "-"
+		if state.AppliedAt != nil {
+			appliedAt = state.AppliedAt.Format(time.RFC3339)
+		}
 
-// loadMigrations walks the given path and loads all the migration files.
-func loadMigrations(path string) ([]*arangom.Migration, error) {
-	migrations := make([]*arangom.Migration, 0)
-	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+		checksum := state.Checksum
+		if checksum == "" {
+			checksum = state.StoredChecksum
 		}
 
-		if !info.IsDir() && strings.HasSuffix(path, ".yaml") {
-			migration, err := loadMigration(path)
+		note := ""
+		switch {
+		case state.Orphaned:
+			note = "no migration file"
+		case state.Changed():
+			note = "changed since applied"
+		case state.OutOfOrder:
+			note = "out of order"
+		}
+
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.12s\t%s\n", state.ID, state.Name, state.Status, appliedAt, checksum, note)
+	}
+
+	return w.Flush()
+}
+
+// runValidate checks the migration files without connecting to the database,
+// printing every problem found.
+func runValidate(order arangom.OrderStrategy) error {
+	if migrationDir == "" {
+		return fmt.Errorf("migration directory is required")
+	}
+
+	if err := arangom.ValidateMigrations(os.DirFS(migrationDir), ".", arangom.WithOrderStrategy(order), arangom.WithVariables(variables)); err != nil {
+		return err
+	}
+
+	fmt.Println("all migrations are valid")
+
+	return nil
+}
+
+// runSchema prints the JSON Schema of the migration files.
+func runSchema() error {
+	schema, err := arangom.JSONSchema()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Println(string(schema))
+
+	return err
+}
+
+// run executes the command connecting to the database.
+func run(ctx context.Context, executor *arangom.Executor, command string, args []string) error {
+	switch command {
+	case "migrate":
+		if dryRun {
+			if target != "" {
+				return fmt.Errorf("the -target flag cannot be used with -dry-run")
+			}
+
+			plan, err := executor.Plan(ctx)
 			if err != nil {
 				return err
 			}
 
-			migrations = append(migrations, migration)
+			for _, planned := range plan.Migrations {
+				if planned.Action == arangom.PlanActionBlock {
+					return fmt.Errorf("migration %d would stop the execution", planned.Migration.ID)
+				}
+			}
+
+			return nil
 		}
 
-		return nil
-	})
+		if target != "" {
+			return executor.ExecuteTo(ctx, target)
+		}
 
-	return migrations, err
+		return executor.Execute(ctx)
+	case "retry":
+		return executor.Retry(ctx)
+	case "rollback":
+		return runRollback(ctx, executor, args)
+	case "status":
+		return runStatus(ctx, executor, args)
+	case "repair":
+		return executor.Repair(ctx)
+	case "force-unlock":
+		return executor.ForceUnlock(ctx)
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
 }
 
 func main() {
+	command := DefaultCommand
+	args := flag.Args()
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	order, err := arangom.ParseOrderStrategy(migrationOrder)
+	if err != nil {
+		panic(err)
+	}
+
+	switch command {
+	case "validate":
+		if err := runValidate(order); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		return
+	case "schema":
+		if err := runSchema(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if err := validateFlags(); err != nil {
+		panic(err)
+	}
+
+	// Only the plan of the migrate command is supported without changing the
+	// database, so fail before the migration collection is created.
+	if dryRun && command != "migrate" && command != "status" {
+		panic(fmt.Errorf("the -dry-run flag cannot be used with the %s command", command))
+	}
+
 	db, conn, err := initDatabase()
 	if err != nil {
 		panic(err)
 	}
 
-	migrations, err := loadMigrations(migrationDir)
+	migrations, err := arangom.LoadMigrations(os.DirFS(migrationDir), ".", arangom.WithOrderStrategy(order), arangom.WithVariables(variables))
+	if err != nil {
+		panic(err)
+	}
+
+	outOfOrderPolicy, err := arangom.ParseOutOfOrderPolicy(outOfOrder)
 	if err != nil {
 		panic(err)
 	}
@@ -180,12 +387,16 @@ func main() {
 		arangom.WithConnection(conn),
 		arangom.WithCollection(collection),
 		arangom.WithMigrations(migrations),
+		arangom.WithLockTimeout(lockWait),
+		arangom.WithAllowChecksumChange(allowChecksumChange),
+		arangom.WithVersion(version),
+		arangom.WithOutOfOrderPolicy(outOfOrderPolicy),
 	)
 	if err != nil {
 		panic(err)
 	}
 
-	if err := executor.Execute(context.Background()); err != nil {
+	if err := run(context.Background(), executor, command, args); err != nil {
 		fmt.Print(err)
 		os.Exit(1)
 	}
@@ -0,0 +1,281 @@
+package arangom
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownField is returned when a migration file has a field that is not
+// part of the schema.
+var ErrUnknownField = fmt.Errorf("unknown field")
+
+// SchemaError is returned when a migration file does not match the schema. It
+// holds the position of the problem in the file.
+type SchemaError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// decodeMigration decodes a YAML or JSON migration file strictly. Unknown
+// fields of the migration and of its operations, as well as option fields
+// unknown to the options of the operation kind, are reported as SchemaError
+// values joined into a single error.
+func decodeMigration(name string, b []byte) (*Migration, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	m := new(Migration)
+	if len(node.Content) == 0 {
+		return m, nil
+	}
+
+	if errs := checkMigrationNode(name, node.Content[0]); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := node.Decode(m); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return m, nil
+}
+
+// checkMigrationNode returns the problems of the migration node.
+func checkMigrationNode(name string, node *yaml.Node) []error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	fields := yamlFields(reflect.TypeFor[Migration]())
+	errs := make([]error, 0)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if !fields[key.Value] {
+			errs = append(errs, schemaError(name, key, fmt.Errorf("%w %q in migration", ErrUnknownField, key.Value)))
+			continue
+		}
+
+		if key.Value == "transaction" && value.Kind == yaml.MappingNode {
+			errs = append(errs, checkTransactionNode(name, value)...)
+			continue
+		}
+
+		if value.Kind != yaml.SequenceNode || (key.Value != "operations" && key.Value != "down") {
+			continue
+		}
+
+		for _, operation := range value.Content {
+			errs = append(errs, checkOperationNode(name, operation)...)
+		}
+	}
+
+	return errs
+}
+
+// checkTransactionNode returns the problems of the transaction node.
+func checkTransactionNode(name string, node *yaml.Node) []error {
+	fields := yamlFields(reflect.TypeFor[MigrationTransaction]())
+	errs := make([]error, 0)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !fields[key.Value] {
+			errs = append(errs, schemaError(name, key, fmt.Errorf("%w %q in transaction", ErrUnknownField, key.Value)))
+		}
+	}
+
+	return errs
+}
+
+// checkOperationNode returns the problems of the operation node, checking its
+// options against the options of its kind.
+func checkOperationNode(name string, node *yaml.Node) []error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	fields := yamlFields(reflect.TypeFor[Operation]())
+	errs := make([]error, 0)
+
+	var (
+		kind        OperationKind
+		knownKind   bool
+		optionsNode *yaml.Node
+	)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		switch {
+		case !fields[key.Value]:
+			errs = append(errs, schemaError(name, key, fmt.Errorf("%w %q in operation", ErrUnknownField, key.Value)))
+		case key.Value == "kind":
+			if kind, knownKind = operationMap[value.Value]; !knownKind {
+				errs = append(errs, schemaError(name, value, fmt.Errorf("%w: %s", ErrInvalidOperationKind, value.Value)))
+			}
+		case key.Value == "options":
+			optionsNode = value
+		}
+	}
+
+	if !knownKind || optionsNode == nil || optionsNode.Kind != yaml.MappingNode {
+		return errs
+	}
+
+	newOpts, ok := operationOptionsMap[kind]
+	if !ok {
+		for i := 0; i+1 < len(optionsNode.Content); i += 2 {
+			key := optionsNode.Content[i]
+			errs = append(errs, schemaError(name, key, fmt.Errorf("%w %q, %s has no options", ErrUnknownField, key.Value, kind)))
+		}
+
+		return errs
+	}
+
+	return append(errs, checkOptionsNode(name, optionsNode, reflect.TypeOf(newOpts()), fmt.Sprintf("options of %s", kind))...)
+}
+
+// checkOptionsNode returns the option fields of the node unknown to the given
+// type, recursing into the nested structs. Like encoding/json, the field names
+// are matched case-insensitively.
+func checkOptionsNode(name string, node *yaml.Node, t reflect.Type, where string) []error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	errs := make([]error, 0)
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type)
+		for name, field := range jsonFields(t) {
+			fields[strings.ToLower(name)] = field
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			field, ok := fields[strings.ToLower(key.Value)]
+			if !ok {
+				errs = append(errs, schemaError(name, key, fmt.Errorf("%w %q in %s", ErrUnknownField, key.Value, where)))
+				continue
+			}
+
+			errs = append(errs, checkOptionsNode(name, value, field, fmt.Sprintf("%s.%s", where, key.Value))...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			errs = append(errs, checkOptionsNode(name, value, t.Elem(), fmt.Sprintf("%s.%s", where, key.Value))...)
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && node.Kind == yaml.SequenceNode:
+		for _, value := range node.Content {
+			errs = append(errs, checkOptionsNode(name, value, t.Elem(), where)...)
+		}
+	}
+
+	return errs
+}
+
+// schemaError returns a SchemaError at the position of the node.
+func schemaError(name string, node *yaml.Node, err error) error {
+	return &SchemaError{
+		Path:   name,
+		Line:   node.Line,
+		Column: node.Column,
+		Err:    err,
+	}
+}
+
+// yamlFields returns the names of the YAML fields of the struct type.
+func yamlFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		switch tag {
+		case "-":
+			continue
+		case "":
+			fields[strings.ToLower(field.Name)] = true
+		default:
+			fields[tag] = true
+		}
+	}
+
+	return fields
+}
+
+// jsonFields returns the types of the JSON fields of the struct type by their
+// name, including the fields of the embedded structs. The fields without JSON
+// name are named after the field in lower camel case, like ArangoDB names them.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for name, fieldType := range jsonFields(embedded) {
+					if _, ok := fields[name]; !ok {
+						fields[name] = fieldType
+					}
+				}
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if tag == "" {
+			tag = strings.ToLower(field.Name[:1]) + field.Name[1:]
+		}
+
+		fields[tag] = field.Type
+	}
+
+	return fields
+}
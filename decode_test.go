@@ -0,0 +1,181 @@
+package arangom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeMigration(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      *Migration
+		wantErrs  []string
+		wantErrIs error
+	}{
+		{
+			name: "decode migration",
+			data: "id: 123\noperations:\n  - kind: createPersistentIndex\n    collection: users\n    options:\n      name: email_idx\n      fields: [email]\n      unique: true\n",
+			want: &Migration{
+				ID: 123,
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindPersistentIndexCreate,
+						Collection: "users",
+						Options: map[string]any{
+							"name":   "email_idx",
+							"fields": []any{"email"},
+							"unique": true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "decode json migration",
+			data: `{"id": 123, "down": [{"kind": "deleteCollection", "collection": "users"}]}`,
+			want: &Migration{
+				ID: 123,
+				Down: []*Operation{
+					{
+						Kind:       OperationKindCollectionDelete,
+						Collection: "users",
+					},
+				},
+			},
+		},
+		{
+			name: "decode migration with transaction",
+			data: "id: 123\ntransaction:\n  write: [users]\n  read: [roles]\noperations:\n  - kind: executeAQL\n    options:\n      query: RETURN 1\n",
+			want: &Migration{
+				ID: 123,
+				Operations: []*Operation{
+					{
+						Kind:    OperationKindAQLExecute,
+						Options: map[string]any{"query": "RETURN 1"},
+					},
+				},
+				Transaction: &MigrationTransaction{
+					Read:  []string{"roles"},
+					Write: []string{"users"},
+				},
+			},
+		},
+		{
+			name:      "decode unknown transaction field",
+			data:      "id: 123\ntransaction:\n  writes: [users]\n",
+			wantErrs:  []string{`migration.yaml:3:3: unknown field "writes" in transaction`},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name: "decode empty migration",
+			data: "",
+			want: &Migration{},
+		},
+		{
+			name:      "decode unknown migration field",
+			data:      "id: 123\noperation:\n  - kind: deleteCollection\n",
+			wantErrs:  []string{`migration.yaml:2:1: unknown field "operation" in migration`},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name:      "decode unknown operation field",
+			data:      "id: 123\noperations:\n  - kind: deleteCollection\n    colection: users\n",
+			wantErrs:  []string{`migration.yaml:4:5: unknown field "colection" in operation`},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name:      "decode invalid operation kind",
+			data:      "id: 123\ndown:\n  - kind: dropCollection\n",
+			wantErrs:  []string{"migration.yaml:3:11: invalid operation kind: dropCollection"},
+			wantErrIs: ErrInvalidOperationKind,
+		},
+		{
+			name:      "decode unknown option field",
+			data:      "id: 123\noperations:\n  - kind: createPersistentIndex\n    collection: users\n    options:\n      fileds: [email]\n",
+			wantErrs:  []string{`migration.yaml:6:7: unknown field "fileds" in options of createPersistentIndex`},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name:      "decode unknown nested option field",
+			data:      "id: 123\noperations:\n  - kind: createCollection\n    collection: users\n    options:\n      schema:\n        levl: strict\n",
+			wantErrs:  []string{`migration.yaml:7:9: unknown field "levl" in options of createCollection.schema`},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name:      "decode options of operation without options",
+			data:      "id: 123\noperations:\n  - kind: deleteCollection\n    collection: users\n    options:\n      force: true\n",
+			wantErrs:  []string{`migration.yaml:6:7: unknown field "force", deleteCollection has no options`},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name: "decode multiple problems",
+			data: "id: 123\noperations:\n  - kind: createCollection\n    colection: users\n    options:\n      waitForSnc: true\n",
+			wantErrs: []string{
+				`migration.yaml:4:5: unknown field "colection" in operation`,
+				`migration.yaml:6:7: unknown field "waitForSnc" in options of createCollection`,
+			},
+			wantErrIs: ErrUnknownField,
+		},
+		{
+			name:     "decode invalid yaml",
+			data:     "id: [\n",
+			wantErrs: []string{"migration.yaml: yaml: line 1: did not find expected node content"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := decodeMigration("migration.yaml", []byte(tt.data))
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("decodeMigration() error = %v", err)
+			}
+
+			if len(tt.wantErrs) > 0 {
+				var joined interface{ Unwrap() []error }
+				errs := []error{err}
+				if errors.As(err, &joined) {
+					errs = joined.Unwrap()
+				}
+
+				if len(errs) != len(tt.wantErrs) {
+					t.Fatalf("decodeMigration() error = %v, want %v", err, tt.wantErrs)
+				}
+
+				for i, err := range errs {
+					if err.Error() != tt.wantErrs[i] {
+						t.Errorf("decodeMigration() error #%d = %v, want %v", i, err, tt.wantErrs[i])
+					}
+				}
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("decodeMigration() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeMigration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	err := &SchemaError{
+		Path:   "migration.yaml",
+		Line:   4,
+		Column: 5,
+		Err:    ErrUnknownField,
+	}
+
+	if err.Error() != "migration.yaml:4:5: unknown field" {
+		t.Errorf("SchemaError.Error() = %v", err.Error())
+	}
+
+	if !errors.Is(err, ErrUnknownField) {
+		t.Errorf("SchemaError does not wrap %v", ErrUnknownField)
+	}
+}
@@ -0,0 +1,213 @@
+package arangom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+
+	"github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultImportBatchSize is the number of documents the importDocuments
+	// operation creates at once if the operation sets no batch size.
+	DefaultImportBatchSize = 1000
+)
+
+var (
+	// ErrInvalidDocuments is returned when the document file of an operation
+	// cannot be read or it is not a JSON array or JSONL file of objects.
+	ErrInvalidDocuments = fmt.Errorf("invalid documents")
+	// ErrDocumentsRejected is returned when the database rejects some of the
+	// documents of an operation.
+	ErrDocumentsRejected = fmt.Errorf("documents rejected")
+
+	// onDuplicateModes are the overwrite modes of the onDuplicate option by
+	// their name. Duplicates are rejected by default.
+	onDuplicateModes = map[string]driver.OverwriteMode{
+		"":        "",
+		"error":   "",
+		"update":  driver.OverwriteModeUpdate,
+		"replace": driver.OverwriteModeReplace,
+		"ignore":  driver.OverwriteModeIgnore,
+	}
+)
+
+// importDocumentsOpts are the options of the importDocuments operation.
+type importDocumentsOpts struct {
+	File        string `json:"file"`
+	BatchSize   int    `json:"batchSize"`
+	OnDuplicate string `json:"onDuplicate"`
+}
+
+// validate returns an error if the batch size or the onDuplicate option is
+// invalid.
+func (o *importDocumentsOpts) validate() error {
+	if o.BatchSize < 0 {
+		return fmt.Errorf("batchSize must not be negative, got %d", o.BatchSize)
+	}
+
+	if _, ok := onDuplicateModes[o.OnDuplicate]; !ok {
+		return fmt.Errorf("onDuplicate must be error, update, replace or ignore, got %q", o.OnDuplicate)
+	}
+
+	return nil
+}
+
+// documentsOpts are the options of the insertDocuments operation.
+type documentsOpts struct {
+	Documents []map[string]any `json:"documents"`
+}
+
+// keyedDocumentsOpts are the options of the upsertDocuments and
+// replaceDocuments operations, whose documents are identified by their key.
+type keyedDocumentsOpts struct {
+	Documents []map[string]any `json:"documents"`
+}
+
+// validate returns an error if a document has no key.
+func (o *keyedDocumentsOpts) validate() error {
+	for i, document := range o.Documents {
+		if key, ok := document["_key"].(string); !ok || key == "" {
+			return fmt.Errorf("document #%d has no _key", i+1)
+		}
+	}
+
+	return nil
+}
+
+// keys returns the keys of the documents.
+func (o *keyedDocumentsOpts) keys() []string {
+	keys := make([]string, 0, len(o.Documents))
+	for _, document := range o.Documents {
+		key, _ := document["_key"].(string)
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// removeDocumentsOpts are the options of the removeDocuments operation.
+type removeDocumentsOpts struct {
+	Keys []string `json:"keys"`
+}
+
+// loadDocumentFiles reads the document files of the importDocuments operations
+// of the migration loaded from the named file. The file option is relative to
+// the directory of the migration file. The content of the document files is
+// kept by the operations, making it part of the checksum of the migration.
+func loadDocumentFiles(fsys fs.FS, name string, m *Migration) error {
+	for _, operation := range slices.Concat(m.Operations, m.Down) {
+		if operation.Kind != OperationKindDocumentsImport {
+			continue
+		}
+
+		opts := importDocumentsOpts{}
+		if err := convertToOperationOptions(operation.Options, &opts); err != nil || opts.File == "" {
+			// The invalid options are reported when the operation runs.
+			continue
+		}
+
+		file := path.Join(path.Dir(name), opts.File)
+
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidDocuments, name, err.Error())
+		}
+
+		if _, err := parseDocuments(file, b); err != nil {
+			return err
+		}
+
+		operation.content = b
+	}
+
+	return nil
+}
+
+// documentFiles returns the set of document files imported by the
+// importDocuments operations of the migration.
+func documentFiles(m *Migration) map[string]bool {
+	files := make(map[string]bool)
+
+	for _, operation := range slices.Concat(m.Operations, m.Down) {
+		if operation.Kind != OperationKindDocumentsImport {
+			continue
+		}
+
+		if file, ok := operation.Options["file"].(string); ok && file != "" {
+			files[path.Join(path.Dir(m.Path), file)] = true
+		}
+	}
+
+	return files
+}
+
+// parseDocuments parses the content of a document file, which is either a
+// JSON array of objects or one JSON object per line. The numbers are kept as
+// written.
+func parseDocuments(name string, b []byte) ([]map[string]any, error) {
+	trimmed := bytes.TrimSpace(b)
+	documents := make([]map[string]any, 0)
+
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&documents); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidDocuments, name, err.Error())
+		}
+
+		if decoder.More() {
+			return nil, fmt.Errorf("%w: %s: unexpected content after the array", ErrInvalidDocuments, name)
+		}
+
+		return documents, nil
+	}
+
+	for i, line := range bytes.Split(b, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+
+		document := make(map[string]any)
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("%w: %s:%d: %s", ErrInvalidDocuments, name, i+1, err.Error())
+		}
+
+		if decoder.More() {
+			return nil, fmt.Errorf("%w: %s:%d: more than one document on the line", ErrInvalidDocuments, name, i+1)
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+// documentErrors returns an error wrapping ErrDocumentsRejected listing the
+// errors of the documents rejected by the database, numbered from offset. If
+// no document is rejected, nil is returned.
+func documentErrors(collection string, offset int, errs driver.ErrorSlice) error {
+	rejected := make([]error, 0)
+
+	for i, err := range errs {
+		if err != nil {
+			rejected = append(rejected, fmt.Errorf("document #%d: %w", offset+i+1, err))
+		}
+	}
+
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s: %w", ErrDocumentsRejected, collection, errors.Join(rejected...))
+}
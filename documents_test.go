@@ -0,0 +1,200 @@
+package arangom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/arangodb/go-driver"
+)
+
+func TestParseDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []map[string]any
+		wantErr string
+	}{
+		{
+			name: "parse json array",
+			data: "[\n  {\"_key\": \"hu\", \"name\": \"Hungary\"},\n  {\"_key\": \"at\", \"population\": 9104772}\n]\n",
+			want: []map[string]any{
+				{"_key": "hu", "name": "Hungary"},
+				{"_key": "at", "population": json.Number("9104772")},
+			},
+		},
+		{
+			name: "parse jsonl",
+			data: "{\"_key\": \"hu\"}\n\n{\"_key\": \"at\"}\n",
+			want: []map[string]any{
+				{"_key": "hu"},
+				{"_key": "at"},
+			},
+		},
+		{
+			name: "parse empty file",
+			data: "",
+			want: []map[string]any{},
+		},
+		{
+			name:    "parse invalid json array",
+			data:    "[{\"_key\": \"hu\"},]",
+			wantErr: "invalid documents: countries.json: invalid character ']' looking for beginning of value",
+		},
+		{
+			name:    "parse json array with trailing content",
+			data:    "[{\"_key\": \"hu\"}] []",
+			wantErr: "invalid documents: countries.json: unexpected content after the array",
+		},
+		{
+			name:    "parse invalid jsonl",
+			data:    "{\"_key\": \"hu\"}\n{\"_key\": at}\n",
+			wantErr: "invalid documents: countries.json:2: invalid character 'a' looking for beginning of value",
+		},
+		{
+			name:    "parse jsonl with two documents on a line",
+			data:    "{\"_key\": \"hu\"} {\"_key\": \"at\"}\n",
+			wantErr: "invalid documents: countries.json:1: more than one document on the line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseDocuments("countries.json", []byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseDocuments() error = %v, wantErr %s", err, tt.wantErr)
+				}
+
+				if !errors.Is(err, ErrInvalidDocuments) {
+					t.Errorf("parseDocuments() error = %v, want %v", err, ErrInvalidDocuments)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseDocuments() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDocuments() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDocumentFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_seed.yaml": {
+			Data: []byte("id: 1\noperations:\n  - kind: importDocuments\n    collection: countries\n    options:\n      file: data/countries.jsonl\n"),
+		},
+		"migrations/2_seed_missing.yaml": {
+			Data: []byte("id: 2\noperations:\n  - kind: importDocuments\n    collection: countries\n    options:\n      file: data/missing.jsonl\n"),
+		},
+		"migrations/3_seed_invalid.yaml": {
+			Data: []byte("id: 3\noperations:\n  - kind: importDocuments\n    collection: countries\n    options:\n      file: data/invalid.json\n"),
+		},
+		"migrations/data/countries.jsonl": {
+			Data: []byte("{\"_key\": \"hu\"}\n"),
+		},
+		"migrations/data/invalid.json": {
+			Data: []byte("[{\"_key\": \"hu\"}\n"),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		wantContent string
+		wantErr     bool
+	}{
+		{
+			name:        "load document file relative to the migration file",
+			path:        "migrations/1_seed.yaml",
+			wantContent: "{\"_key\": \"hu\"}\n",
+		},
+		{
+			name:    "load missing document file",
+			path:    "migrations/2_seed_missing.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "load invalid document file",
+			path:    "migrations/3_seed_invalid.yaml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadMigration(fsys, tt.path)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDocuments) {
+					t.Fatalf("LoadMigration() error = %v, want %v", err, ErrInvalidDocuments)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadMigration() error = %v", err)
+			}
+
+			if content := string(got.Operations[0].content); content != tt.wantContent {
+				t.Errorf("LoadMigration() content = %q, want %q", content, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  int
+		errs    driver.ErrorSlice
+		wantErr string
+	}{
+		{
+			name:   "no rejected documents",
+			offset: 0,
+			errs:   driver.ErrorSlice{nil, nil},
+		},
+		{
+			name:    "rejected documents",
+			offset:  100,
+			errs:    driver.ErrorSlice{nil, fmt.Errorf("unique constraint violated"), nil, fmt.Errorf("invalid key")},
+			wantErr: "documents rejected: countries: document #102: unique constraint violated\ndocument #104: invalid key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := documentErrors("countries", tt.offset, tt.errs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("documentErrors() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("documentErrors() error = %v, wantErr %s", err, tt.wantErr)
+			}
+
+			if !errors.Is(err, ErrDocumentsRejected) {
+				t.Errorf("documentErrors() error = %v, want %v", err, ErrDocumentsRejected)
+			}
+		})
+	}
+}
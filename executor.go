@@ -2,6 +2,10 @@ package arangom
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/arangodb/go-driver"
 	"github.com/pkg/errors"
@@ -80,16 +84,66 @@ func WithLogger(logger Logger) ExecutorOption {
 	}
 }
 
+// WithLockTimeout sets the time to wait for the migration lock held by an
+// other executor. If the timeout is zero, the executor does not wait.
+func WithLockTimeout(timeout time.Duration) ExecutorOption {
+	return func(e *Executor) error {
+		if timeout < 0 {
+			return ErrInvalidLockTimeout
+		}
+
+		e.lockTimeout = timeout
+		return nil
+	}
+}
+
+// WithLockTTL sets the time after the migration lock is considered stale if
+// its owner stops sending heartbeats.
+func WithLockTTL(ttl time.Duration) ExecutorOption {
+	return func(e *Executor) error {
+		if ttl <= 0 {
+			return ErrInvalidLockTTL
+		}
+
+		e.lockTTL = ttl
+		return nil
+	}
+}
+
+// WithAllowChecksumChange sets whether applied migrations may change. If
+// allowed, the recorded checksum of changed migrations is updated instead of
+// failing the execution.
+func WithAllowChecksumChange(allow bool) ExecutorOption {
+	return func(e *Executor) error {
+		e.allowChecksumChange = allow
+		return nil
+	}
+}
+
+// WithVersion sets the version of arangom recorded along with the migrations.
+func WithVersion(version string) ExecutorOption {
+	return func(e *Executor) error {
+		e.version = version
+		return nil
+	}
+}
+
 // Executor executes migrations on a database in order.
 type Executor struct {
-	db         driver.Database
-	conn       driver.Connection
-	collection string
-	migrations []*Migration
-	logger     Logger
+	db                  driver.Database
+	conn                driver.Connection
+	collection          string
+	migrations          []*Migration
+	logger              Logger
+	lockTimeout         time.Duration
+	lockTTL             time.Duration
+	allowChecksumChange bool
+	version             string
+	outOfOrderPolicy    OutOfOrderPolicy
 }
 
-// Execute executes the migrations on the database.
+// Execute executes the migrations on the database. The migration lock is held
+// during the execution, so only one executor runs the migrations at a time.
 func (e *Executor) Execute(ctx context.Context) error {
 	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
 	coll, err := e.db.Collection(ctx, e.collection)
@@ -97,55 +151,461 @@ func (e *Executor) Execute(ctx context.Context) error {
 		return err
 	}
 
+	return e.withLock(ctx, coll, func(ctx context.Context) error {
+		if err := e.execute(ctx, coll, e.migrations, false); err != nil {
+			return err
+		}
+
+		e.logger.Info("all migrations executed successfully")
+
+		return nil
+	})
+}
+
+// ExecuteTo executes the pending migrations up to and including the target
+// migration. The target is matched against the ID and the name of the
+// migrations. The pending migrations following the target are left pending
+// and they are reported in the log.
+func (e *Executor) ExecuteTo(ctx context.Context, target string) error {
+	index := e.migrationIndex(target)
+	if index < 0 {
+		return errors.Wrap(ErrMigrationNotFound, target)
+	}
+
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	coll, err := e.db.Collection(ctx, e.collection)
+	if err != nil {
+		return err
+	}
+
+	return e.withLock(ctx, coll, func(ctx context.Context) error {
+		if err := e.execute(ctx, coll, e.migrations[:index+1], false); err != nil {
+			return err
+		}
+
+		e.logger.Infof("all migrations executed successfully up to %s", target)
+		e.reportPending(e.migrations[index+1:])
+
+		return nil
+	})
+}
+
+// Retry executes the migrations on the database like Execute, but failed or
+// interrupted migrations are resumed from their first unfinished operation
+// instead of stopping the execution. Operations are identified by their index,
+// therefore the operations completed before the failure must not be changed.
+// Migrations that failed to roll back are not resumed, they must be rolled
+// back again.
+func (e *Executor) Retry(ctx context.Context) error {
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	coll, err := e.db.Collection(ctx, e.collection)
+	if err != nil {
+		return err
+	}
+
+	return e.withLock(ctx, coll, func(ctx context.Context) error {
+		if err := e.execute(ctx, coll, e.migrations, true); err != nil {
+			return err
+		}
+
+		e.logger.Info("all migrations executed successfully")
+
+		return nil
+	})
+}
+
+// execute executes the given migrations in order, skipping the ones already
+// executed. If retry is true, unfinished migrations are resumed. Before executing
+// any migration, the status of every migration is fetched and the order of
+// the migrations is checked.
+func (e *Executor) execute(ctx context.Context, coll driver.Collection, migrations []*Migration, retry bool) error {
 	for _, migration := range e.migrations {
-		e.logger.Infof("[%d] fetching migration status", migration.ID)
-		fetchMigrationStatus(ctx, coll, migration)
+		if err := e.fetchStatus(ctx, coll, migration); err != nil {
+			return err
+		}
+
+		if err := e.upgradeRecord(ctx, coll, migration); err != nil {
+			return err
+		}
+	}
+
+	if err := e.checkOrder(); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.Status.isRollingBack() {
+			e.logger.Errorf("[%d] migration is partially rolled back, roll it back again", migration.ID)
+			return ErrRollbackFailed
+		}
 
 		// Skip migrations that have already been run.
-		if migration.Status != MigrationStatusMissing {
-			if migration.Status == MigrationStatusFailed {
-				e.logger.Errorf("[%d] migration failed", migration.ID)
-				return ErrMigrationFailed
+		if !migration.Status.IsPending() && !migration.Status.isUnfinished() {
+			if err := e.checkChecksum(ctx, coll, migration); err != nil {
+				return err
 			}
 
 			e.logger.Infof("[%d] migration already executed", migration.ID)
 			continue
 		}
 
+		if migration.Status.isUnfinished() && !retry {
+			if migration.Status == MigrationStatusRunning {
+				e.logger.Errorf("[%d] migration was interrupted", migration.ID)
+			} else {
+				e.logger.Errorf("[%d] migration failed", migration.ID)
+			}
+
+			return ErrMigrationFailed
+		}
+
+		if err := e.migrate(ctx, coll, migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportPending logs the given migrations that are not executed yet.
+func (e *Executor) reportPending(migrations []*Migration) {
+	pending := 0
+	for _, migration := range migrations {
+		if migration.Status.IsPending() || migration.Status.isUnfinished() {
+			e.logger.Infof("[%d] migration left pending", migration.ID)
+			pending++
+		}
+	}
+
+	e.logger.Infof("%d migrations left pending", pending)
+}
+
+// migrate executes the migration and records its progress after every
+// operation. Unfinished migrations are resumed from their first unfinished
+// operation.
+func (e *Executor) migrate(ctx context.Context, coll driver.Collection, migration *Migration) error {
+	if migration.Status.isUnfinished() {
+		e.logger.Infof("[%d] resuming migration after %d completed operations", migration.ID, len(migration.completed))
+	} else {
 		e.logger.Infof("[%d] executing migration", migration.ID)
-		migration.Status = MigrationStatusRunning
-		saveMigration(ctx, coll, migration)
+		migration.completed = nil
+		migration.checkpoints = nil
+	}
+
+	migration.start()
+	if err := e.saveStatus(ctx, coll, migration, MigrationStatusRunning); err != nil {
+		return err
+	}
+
+	completed := slices.Clone(migration.completed)
+	err := migration.migrate(e.migrationContext(ctx), e.db, completed, func(index int) error {
+		migration.completed = append(migration.completed, index)
+		delete(migration.checkpoints, index)
+		return e.saveStatus(ctx, coll, migration, MigrationStatusRunning)
+	}, func(index int) *operationProgress {
+		return &operationProgress{
+			checkpoint: migration.checkpoints[index],
+			logger:     e.logger,
+			save: func(checkpoint string) error {
+				if migration.checkpoints == nil {
+					migration.checkpoints = make(map[int]string)
+				}
+
+				migration.checkpoints[index] = checkpoint
+				return e.saveStatus(ctx, coll, migration, MigrationStatusRunning)
+			},
+		}
+	})
+	if err != nil {
+		// The migration error is more relevant than failing to save its
+		// status, which is logged by saveStatus.
+		migration.finish(MigrationStatusFailed, err)
+		_ = e.saveStatus(ctx, coll, migration, MigrationStatusFailed)
 
-		migrateCtx := ctx
-		if e.conn != nil {
-			migrateCtx = WithConnectionContext(ctx, e.conn)
+		err = errors.Wrap(err, ErrMigrationFailed.Error())
+		e.logger.Errorf("[%d] migration failed; err=%s", migration.ID, err.Error())
+		return err
+	}
+
+	e.logger.Infof("[%d] migration executed successfully", migration.ID)
+	migration.finish(MigrationStatusDone, nil)
+	if err := saveMigration(ctx, coll, migration, e.version); err != nil {
+		err = &MigrationError{
+			ID:    migration.ID,
+			Phase: MigrationPhaseSaveStatus,
+			Err:   fmt.Errorf("%w: %w", ErrMigrationNotRecorded, err),
 		}
+		e.logger.Errorf("[%d] failed to record migration as done; err=%s", migration.ID, err.Error())
+		return err
+	}
 
-		if err := migration.Migrate(migrateCtx, e.db); err != nil {
-			migration.Status = MigrationStatusFailed
-			saveMigration(ctx, coll, migration)
+	return nil
+}
+
+// Rollback rolls back the given number of applied migrations, starting from the
+// latest one. Migrations that failed to roll back earlier are rolled back again.
+func (e *Executor) Rollback(ctx context.Context, steps int) error {
+	if steps < 1 {
+		return ErrInvalidRollbackSteps
+	}
 
-			err = errors.Wrap(err, ErrMigrationFailed.Error())
-			e.logger.Errorf("[%d] migration failed; err=%s", migration.ID, err.Error())
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	coll, err := e.db.Collection(ctx, e.collection)
+	if err != nil {
+		return err
+	}
+
+	return e.withLock(ctx, coll, func(ctx context.Context) error {
+		applied := make([]*Migration, 0, steps)
+		for i := len(e.migrations) - 1; i >= 0 && len(applied) < steps; i-- {
+			migration := e.migrations[i]
+
+			if err := e.fetchStatus(ctx, coll, migration); err != nil {
+				return err
+			}
+
+			if err := e.upgradeRecord(ctx, coll, migration); err != nil {
+				return err
+			}
+
+			if migration.Status == MigrationStatusDone || migration.Status.isRollingBack() {
+				applied = append(applied, migration)
+			}
+		}
+
+		return e.rollback(ctx, coll, applied)
+	})
+}
+
+// RollbackTo rolls back every applied migration that follows the target
+// migration. The target is matched against the ID and the name of the
+// migrations and it is not rolled back itself.
+func (e *Executor) RollbackTo(ctx context.Context, target string) error {
+	index := e.migrationIndex(target)
+	if index < 0 {
+		return errors.Wrap(ErrMigrationNotFound, target)
+	}
+
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	coll, err := e.db.Collection(ctx, e.collection)
+	if err != nil {
+		return err
+	}
+
+	return e.withLock(ctx, coll, func(ctx context.Context) error {
+		applied := make([]*Migration, 0, len(e.migrations)-index-1)
+		for i := len(e.migrations) - 1; i > index; i-- {
+			migration := e.migrations[i]
+
+			if err := e.fetchStatus(ctx, coll, migration); err != nil {
+				return err
+			}
+
+			if err := e.upgradeRecord(ctx, coll, migration); err != nil {
+				return err
+			}
+
+			if migration.Status == MigrationStatusDone || migration.Status.isRollingBack() {
+				applied = append(applied, migration)
+			}
+		}
+
+		return e.rollback(ctx, coll, applied)
+	})
+}
+
+// rollback rolls back the given migrations in the given order.
+func (e *Executor) rollback(ctx context.Context, coll driver.Collection, migrations []*Migration) error {
+	// Refuse to start if any of the migrations cannot be rolled back, so the
+	// database is not left in a half rolled back state.
+	for _, migration := range migrations {
+		if _, err := migration.DownOperations(); err != nil {
+			e.logger.Errorf("[%d] migration cannot be rolled back; err=%s", migration.ID, err.Error())
+			return err
+		}
+	}
+
+	for _, migration := range migrations {
+		e.logger.Infof("[%d] rolling back migration", migration.ID)
+		migration.start()
+		// The forward operations are undone by the rollback, so they must not
+		// be skipped when the migration is executed again.
+		migration.completed = nil
+		migration.checkpoints = nil
+		if err := e.saveStatus(ctx, coll, migration, MigrationStatusRollingBack); err != nil {
+			return err
+		}
+
+		if err := migration.Rollback(e.migrationContext(ctx), e.db); err != nil {
+			migration.finish(MigrationStatusRollbackFailed, err)
+			_ = e.saveStatus(ctx, coll, migration, MigrationStatusRollbackFailed)
+
+			err = errors.Wrap(err, ErrRollbackFailed.Error())
+			e.logger.Errorf("[%d] rollback failed; err=%s", migration.ID, err.Error())
 			return err
 		}
 
-		e.logger.Infof("[%d] migration executed successfully", migration.ID)
-		migration.Status = MigrationStatusDone
-		saveMigration(ctx, coll, migration)
+		e.logger.Infof("[%d] migration rolled back successfully", migration.ID)
+		migration.finish(MigrationStatusRolledBack, nil)
+		if err := e.saveStatus(ctx, coll, migration, MigrationStatusRolledBack); err != nil {
+			return err
+		}
 	}
 
-	e.logger.Info("all migrations executed successfully")
+	e.logger.Info("all migrations rolled back successfully")
 
 	return nil
 }
 
+// Repair updates the recorded checksum of the applied migrations that changed
+// since they were executed.
+func (e *Executor) Repair(ctx context.Context) error {
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	coll, err := e.db.Collection(ctx, e.collection)
+	if err != nil {
+		return err
+	}
+
+	return e.withLock(ctx, coll, func(ctx context.Context) error {
+		for _, migration := range e.migrations {
+			if err := e.fetchStatus(ctx, coll, migration); err != nil {
+				return err
+			}
+
+			if err := e.upgradeRecord(ctx, coll, migration); err != nil {
+				return err
+			}
+
+			err := migration.VerifyChecksum()
+			if err == nil {
+				continue
+			}
+
+			if !errors.Is(err, ErrChecksumMismatch) {
+				return err
+			}
+
+			e.logger.Infof("[%d] updating the recorded checksum; %s", migration.ID, err.Error())
+			if err := e.saveStatus(ctx, coll, migration, migration.Status); err != nil {
+				return err
+			}
+		}
+
+		e.logger.Info("all migrations repaired successfully")
+
+		return nil
+	})
+}
+
+// checkChecksum compares the recorded checksum of the applied migration to its
+// current checksum. If the checksum changed, the recorded checksum is updated
+// when checksum changes are allowed, otherwise ErrChecksumMismatch is returned.
+func (e *Executor) checkChecksum(ctx context.Context, coll driver.Collection, migration *Migration) error {
+	err := migration.VerifyChecksum()
+	if err == nil || !errors.Is(err, ErrChecksumMismatch) {
+		return err
+	}
+
+	if !e.allowChecksumChange {
+		e.logger.Errorf("[%d] migration changed since it was executed; err=%s", migration.ID, err.Error())
+		return err
+	}
+
+	e.logger.Infof("[%d] migration changed since it was executed, updating the recorded checksum; %s", migration.ID, err.Error())
+
+	return e.saveStatus(ctx, coll, migration, migration.Status)
+}
+
+// fetchStatus fetches the status of the migration from the migration
+// collection.
+func (e *Executor) fetchStatus(ctx context.Context, coll driver.Collection, migration *Migration) error {
+	e.logger.Infof("[%d] fetching migration status", migration.ID)
+	if err := fetchMigrationStatus(ctx, coll, migration); err != nil {
+		err = &MigrationError{
+			ID:    migration.ID,
+			Phase: MigrationPhaseFetchStatus,
+			Err:   err,
+		}
+		e.logger.Errorf("[%d] failed to fetch migration status; err=%s", migration.ID, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// upgradeRecord re-keys the record of the migration created by an earlier
+// version, which is keyed by the checksum of the migration. The recorded
+// checksum is kept, so once the record is keyed by the migration, changing the
+// migration file is detected as a checksum mismatch instead of the migration
+// being reported as missing.
+func (e *Executor) upgradeRecord(ctx context.Context, coll driver.Collection, migration *Migration) error {
+	if migration.record == nil || migration.record.Key == "" || migration.record.Key == migration.Key() {
+		return nil
+	}
+
+	e.logger.Infof("[%d] re-keying the migration record created by an earlier version", migration.ID)
+	if err := saveMigrationItem(ctx, coll, migration, e.version, migration.StoredChecksum()); err != nil {
+		err = &MigrationError{
+			ID:    migration.ID,
+			Phase: MigrationPhaseSaveStatus,
+			Err:   err,
+		}
+		e.logger.Errorf("[%d] failed to save migration status; err=%s", migration.ID, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// saveStatus sets the status of the migration and saves it to the migration
+// collection.
+func (e *Executor) saveStatus(ctx context.Context, coll driver.Collection, migration *Migration, status MigrationStatus) error {
+	migration.Status = status
+	if err := saveMigration(ctx, coll, migration, e.version); err != nil {
+		err = &MigrationError{
+			ID:    migration.ID,
+			Phase: MigrationPhaseSaveStatus,
+			Err:   err,
+		}
+		e.logger.Errorf("[%d] failed to save migration status; err=%s", migration.ID, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// migrationContext returns the context passed to the operations of the
+// migrations.
+func (e *Executor) migrationContext(ctx context.Context) context.Context {
+	if e.conn != nil {
+		return WithConnectionContext(ctx, e.conn)
+	}
+
+	return ctx
+}
+
+// migrationIndex returns the index of the migration matching the target by ID
+// or name, or -1 if no migration matches.
+func (e *Executor) migrationIndex(target string) int {
+	for i, migration := range e.migrations {
+		if strconv.Itoa(migration.ID) == target || migration.Name() == target {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // NewExecutor creates a new Executor. If no migrations are provided, an error
 // is returned.
 func NewExecutor(opts ...ExecutorOption) (*Executor, error) {
 	e := &Executor{
-		collection: DefaultMigrationCollection,
-		logger:     NewDefaultLogger(),
+		collection:       DefaultMigrationCollection,
+		logger:           NewDefaultLogger(),
+		lockTimeout:      DefaultLockTimeout,
+		lockTTL:          DefaultLockTTL,
+		version:          buildVersion(),
+		outOfOrderPolicy: OutOfOrderFail,
 	}
 
 	for _, opt := range opts {
@@ -2,9 +2,11 @@ package arangom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/arangodb/go-driver"
 	"github.com/stretchr/testify/mock"
@@ -245,6 +247,152 @@ func TestWithLogger(t *testing.T) {
 	}
 }
 
+func TestWithLockTimeout(t *testing.T) {
+	type fields struct {
+		executor *Executor
+	}
+	type args struct {
+		timeout time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *Executor
+		wantErr bool
+	}{
+		{
+			name: "with lock timeout",
+			fields: fields{
+				executor: new(Executor),
+			},
+			args: args{
+				timeout: time.Minute,
+			},
+			want: &Executor{
+				lockTimeout: time.Minute,
+			},
+		},
+		{
+			name: "with zero lock timeout",
+			fields: fields{
+				executor: &Executor{
+					lockTimeout: time.Minute,
+				},
+			},
+			args: args{
+				timeout: 0,
+			},
+			want: new(Executor),
+		},
+		{
+			name: "with negative lock timeout",
+			fields: fields{
+				executor: new(Executor),
+			},
+			args: args{
+				timeout: -time.Minute,
+			},
+			want:    new(Executor),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := WithLockTimeout(tt.args.timeout)(tt.fields.executor); (err != nil) != tt.wantErr {
+				t.Errorf("WithLockTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(tt.fields.executor, tt.want) {
+				t.Errorf("WithLockTimeout() = %v, want %v", tt.fields.executor, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLockTTL(t *testing.T) {
+	type fields struct {
+		executor *Executor
+	}
+	type args struct {
+		ttl time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *Executor
+		wantErr bool
+	}{
+		{
+			name: "with lock TTL",
+			fields: fields{
+				executor: new(Executor),
+			},
+			args: args{
+				ttl: time.Minute,
+			},
+			want: &Executor{
+				lockTTL: time.Minute,
+			},
+		},
+		{
+			name: "with zero lock TTL",
+			fields: fields{
+				executor: new(Executor),
+			},
+			args: args{
+				ttl: 0,
+			},
+			want:    new(Executor),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := WithLockTTL(tt.args.ttl)(tt.fields.executor); (err != nil) != tt.wantErr {
+				t.Errorf("WithLockTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(tt.fields.executor, tt.want) {
+				t.Errorf("WithLockTTL() = %v, want %v", tt.fields.executor, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAllowChecksumChange(t *testing.T) {
+	t.Parallel()
+
+	e := new(Executor)
+	if err := WithAllowChecksumChange(true)(e); err != nil {
+		t.Errorf("WithAllowChecksumChange() error = %v", err)
+	}
+
+	if !e.allowChecksumChange {
+		t.Errorf("WithAllowChecksumChange() = %v, want %v", e.allowChecksumChange, true)
+	}
+}
+
+func TestWithVersion(t *testing.T) {
+	t.Parallel()
+
+	e := new(Executor)
+	if err := WithVersion("v1.0.0")(e); err != nil {
+		t.Errorf("WithVersion() error = %v", err)
+	}
+
+	if e.version != "v1.0.0" {
+		t.Errorf("WithVersion() = %v, want %v", e.version, "v1.0.0")
+	}
+}
+
 func TestNewExecutor(t *testing.T) {
 	type args struct {
 		opts []ExecutorOption
@@ -277,7 +425,11 @@ func TestNewExecutor(t *testing.T) {
 						ID: 123,
 					},
 				},
-				logger: new(MockLogger),
+				logger:           new(MockLogger),
+				lockTimeout:      DefaultLockTimeout,
+				lockTTL:          DefaultLockTTL,
+				version:          buildVersion(),
+				outOfOrderPolicy: OutOfOrderFail,
 			},
 		},
 		{
@@ -332,7 +484,11 @@ func TestNewExecutor(t *testing.T) {
 						ID: 123,
 					},
 				},
-				logger: new(MockLogger),
+				logger:           new(MockLogger),
+				lockTimeout:      DefaultLockTimeout,
+				lockTTL:          DefaultLockTTL,
+				version:          buildVersion(),
+				outOfOrderPolicy: OutOfOrderFail,
 			},
 		},
 		{
@@ -384,11 +540,11 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "execute migrations",
 			fields: fields{
 				db: func() driver.Database {
-					ctx := context.Background()
-					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+					ctx := mock.Anything
 
 					coll := new(MockArangoCollection)
-					coll.On("DocumentExists", ctx, checksum).Return(false, nil)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
 					coll.On("CreateDocument", ctx, mock.Anything).Return(driver.DocumentMeta{}, nil)
 
 					db := new(MockArangoDB)
@@ -420,7 +576,7 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "execute on invalid collection",
 			fields: fields{
 				db: func() driver.Database {
-					ctx := context.Background()
+					ctx := mock.Anything
 					db := new(MockArangoDB)
 					db.On("Collection", ctx, "test").Return(nil, fmt.Errorf("error"))
 					return db
@@ -446,11 +602,11 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "execute without migrations",
 			fields: fields{
 				db: func() driver.Database {
-					ctx := context.Background()
-					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+					ctx := mock.Anything
 
 					coll := new(MockArangoCollection)
-					coll.On("DocumentExists", ctx, checksum).Return(false, nil)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
 					coll.On("CreateDocument", ctx, mock.Anything).Return(driver.DocumentMeta{}, nil)
 
 					db := new(MockArangoDB)
@@ -475,11 +631,11 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "execute with migration error",
 			fields: fields{
 				db: func() driver.Database {
-					ctx := context.Background()
-					checksum := "99a2534f50213b0508bddd9e0b1d908d381adfc8e9c1897a2df9a7cd9ec1bb4f"
+					ctx := mock.Anything
 
 					coll := new(MockArangoCollection)
-					coll.On("DocumentExists", ctx, checksum).Return(false, nil)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
 					coll.On("CreateDocument", ctx, mock.Anything).Return(driver.DocumentMeta{}, nil)
 
 					db := new(MockArangoDB)
@@ -523,14 +679,16 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "execute applied migration",
 			fields: fields{
 				db: func() driver.Database {
-					ctx := context.Background()
+					ctx := mock.Anything
 					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
 
 					coll := new(MockArangoCollection)
-					coll.On("DocumentExists", ctx, checksum).Return(true, nil)
-					coll.On("ReadDocument", ctx, checksum, mock.Anything).Return(&MigrationItem{
-						Key:    "123",
-						Status: MigrationStatusDone,
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:      "123",
+						Checksum: checksum,
+						Status:   MigrationStatusDone,
 					}, driver.DocumentMeta{}, nil)
 
 					db := new(MockArangoDB)
@@ -561,14 +719,16 @@ func TestExecutor_Execute(t *testing.T) {
 			name: "execute failed migration",
 			fields: fields{
 				db: func() driver.Database {
-					ctx := context.Background()
+					ctx := mock.Anything
 					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
 
 					coll := new(MockArangoCollection)
-					coll.On("DocumentExists", ctx, checksum).Return(true, nil)
-					coll.On("ReadDocument", ctx, checksum, mock.Anything).Return(&MigrationItem{
-						Key:    "123",
-						Status: MigrationStatusFailed,
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:      "123",
+						Checksum: checksum,
+						Status:   MigrationStatusFailed,
 					}, driver.DocumentMeta{}, nil)
 
 					db := new(MockArangoDB)
@@ -606,6 +766,7 @@ func TestExecutor_Execute(t *testing.T) {
 				collection: tt.fields.collection,
 				migrations: tt.fields.migrations,
 				logger:     tt.fields.logger,
+				lockTTL:    DefaultLockTTL,
 			}
 
 			if err := e.Execute(tt.args.ctx); (err != nil) != tt.wantErr {
@@ -614,3 +775,1201 @@ func TestExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutor_Rollback(t *testing.T) {
+	type fields struct {
+		db         driver.Database
+		collection string
+		migrations []*Migration
+		logger     Logger
+	}
+	type args struct {
+		ctx   context.Context
+		steps int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "rollback latest migration",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := mock.Anything
+					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+					coll := new(MockArangoCollection)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:      "123",
+						Checksum: checksum,
+						Status:   MigrationStatusDone,
+					}, driver.DocumentMeta{}, nil)
+					coll.On("UpdateDocument", ctx, "123", mock.Anything).Return(driver.DocumentMeta{}, nil)
+					coll.On("Remove", ctx).Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+					db.On("Collection", ctx, "users").Return(coll, nil)
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+						Down: []*Operation{
+							{
+								Kind:       OperationKindCollectionDelete,
+								Collection: "users",
+							},
+						},
+					},
+					{
+						ID: 124,
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{124}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+					logger.On("Infof", "[%d] rolling back migration", []any{123}).Return()
+					logger.On("Infof", "[%d] migration rolled back successfully", []any{123}).Return()
+					logger.On("Info", []any{"all migrations rolled back successfully"}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx:   context.Background(),
+				steps: 1,
+			},
+		},
+		{
+			name: "rollback partially rolled back migration",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := mock.Anything
+					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+					coll := new(MockArangoCollection)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:      "123",
+						Checksum: checksum,
+						Status:   MigrationStatusRollbackFailed,
+					}, driver.DocumentMeta{}, nil)
+					coll.On("UpdateDocument", ctx, "123", mock.Anything).Return(driver.DocumentMeta{}, nil)
+					coll.On("Remove", ctx).Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+					db.On("Collection", ctx, "users").Return(coll, nil)
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+						Down: []*Operation{
+							{
+								Kind:       OperationKindCollectionDelete,
+								Collection: "users",
+							},
+						},
+					},
+					{
+						ID: 124,
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{124}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+					logger.On("Infof", "[%d] rolling back migration", []any{123}).Return()
+					logger.On("Infof", "[%d] migration rolled back successfully", []any{123}).Return()
+					logger.On("Info", []any{"all migrations rolled back successfully"}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx:   context.Background(),
+				steps: 1,
+			},
+		},
+		{
+			name: "rollback with invalid steps",
+			fields: fields{
+				db:         new(MockArangoDB),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+				},
+				logger: new(MockLogger),
+			},
+			args: args{
+				ctx:   context.Background(),
+				steps: 0,
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollback irreversible migration",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := mock.Anything
+
+					coll := new(MockArangoCollection)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(true, nil)
+					coll.On("ReadDocument", ctx, mock.Anything, mock.Anything).Return(&MigrationItem{
+						Status: MigrationStatusDone,
+					}, driver.DocumentMeta{}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+						Operations: []*Operation{
+							{
+								Kind: OperationKindAQLExecute,
+								Options: map[string]any{
+									"query": "FOR doc IN users REMOVE doc IN users",
+								},
+							},
+						},
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+					logger.On("Errorf", "[%d] migration cannot be rolled back; err=%s", []any{123, "irreversible migration: blocking operations: #1 executeAQL"}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx:   context.Background(),
+				steps: 1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollback with operation error",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := mock.Anything
+					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+					coll := new(MockArangoCollection)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:                 "123",
+						Checksum:            checksum,
+						Status:              MigrationStatusDone,
+						CompletedOperations: []int{0},
+					}, driver.DocumentMeta{}, nil)
+					coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+						return item.Status == MigrationStatusRollingBack && len(item.CompletedOperations) == 0
+					})).Return(driver.DocumentMeta{}, nil).Once()
+					coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+						return item.Status == MigrationStatusRollbackFailed && len(item.CompletedOperations) == 0
+					})).Return(driver.DocumentMeta{}, nil).Once()
+
+					db := new(MockArangoDB)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+					db.On("Collection", ctx, "users").Return(nil, fmt.Errorf("error"))
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+						Down: []*Operation{
+							{
+								Kind:       OperationKindCollectionDelete,
+								Collection: "users",
+							},
+						},
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+					logger.On("Infof", "[%d] rolling back migration", []any{123}).Return()
+					logger.On("Errorf", "[%d] rollback failed; err=%s", []any{123, "rollback failed: error"}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx:   context.Background(),
+				steps: 1,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.fields.db,
+				collection: tt.fields.collection,
+				migrations: tt.fields.migrations,
+				logger:     tt.fields.logger,
+				lockTTL:    DefaultLockTTL,
+			}
+
+			if err := e.Rollback(tt.args.ctx, tt.args.steps); (err != nil) != tt.wantErr {
+				t.Errorf("Executor.Rollback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecutor_RollbackTo(t *testing.T) {
+	type fields struct {
+		db         driver.Database
+		collection string
+		migrations []*Migration
+		logger     Logger
+	}
+	type args struct {
+		ctx    context.Context
+		target string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "rollback to migration",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := mock.Anything
+					checksum := "86382b50ded6794f123a816744e6f4564e3c13a0a729a04068337e2e95a236e7"
+
+					coll := new(MockArangoCollection)
+					mockLock(coll)
+					coll.On("DocumentExists", ctx, "124").Return(true, nil)
+					coll.On("ReadDocument", ctx, "124", mock.Anything).Return(&MigrationItem{
+						Key:      "124",
+						Checksum: checksum,
+						Status:   MigrationStatusDone,
+					}, driver.DocumentMeta{}, nil)
+					coll.On("UpdateDocument", ctx, "124", mock.Anything).Return(driver.DocumentMeta{}, nil)
+					coll.On("Remove", ctx).Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+					db.On("Collection", ctx, "users").Return(coll, nil)
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+					{
+						ID: 124,
+						Down: []*Operation{
+							{
+								Kind:       OperationKindCollectionDelete,
+								Collection: "users",
+							},
+						},
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{124}).Return()
+					logger.On("Infof", "[%d] rolling back migration", []any{124}).Return()
+					logger.On("Infof", "[%d] migration rolled back successfully", []any{124}).Return()
+					logger.On("Info", []any{"all migrations rolled back successfully"}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx:    context.Background(),
+				target: "123",
+			},
+		},
+		{
+			name: "rollback to unknown migration",
+			fields: fields{
+				db:         new(MockArangoDB),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+				},
+				logger: new(MockLogger),
+			},
+			args: args{
+				ctx:    context.Background(),
+				target: "999",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.fields.db,
+				collection: tt.fields.collection,
+				migrations: tt.fields.migrations,
+				logger:     tt.fields.logger,
+				lockTTL:    DefaultLockTTL,
+			}
+
+			if err := e.RollbackTo(tt.args.ctx, tt.args.target); (err != nil) != tt.wantErr {
+				t.Errorf("Executor.RollbackTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_statusErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		db          func() driver.Database
+		logger      func() Logger
+		wantPhase   MigrationPhase
+		wantNotDone bool
+	}{
+		{
+			name: "fetch status error",
+			db: func() driver.Database {
+				ctx := mock.Anything
+
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, fmt.Errorf("network error"))
+
+				db := new(MockArangoDB)
+				db.On("Collection", ctx, "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+				logger.On("Errorf", "[%d] failed to fetch migration status; err=%s", []any{123, "migration 123: fetch status: network error"}).Return()
+				return logger
+			},
+			wantPhase: MigrationPhaseFetchStatus,
+		},
+		{
+			name: "save running status error",
+			db: func() driver.Database {
+				ctx := mock.Anything
+
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+				coll.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Return(driver.DocumentMeta{}, fmt.Errorf("network error"))
+
+				db := new(MockArangoDB)
+				db.On("Collection", ctx, "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+				logger.On("Infof", "[%d] executing migration", []any{123}).Return()
+				logger.On("Errorf", "[%d] failed to save migration status; err=%s", []any{123, "migration 123: save status: network error"}).Return()
+				return logger
+			},
+			wantPhase: MigrationPhaseSaveStatus,
+		},
+		{
+			name: "save done status error",
+			db: func() driver.Database {
+				ctx := mock.Anything
+
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil).Times(3)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, fmt.Errorf("network error"))
+				coll.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Return(driver.DocumentMeta{}, nil)
+
+				db := new(MockArangoDB)
+				db.On("Collection", ctx, "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+				logger.On("Infof", "[%d] executing migration", []any{123}).Return()
+				logger.On("Infof", "[%d] migration executed successfully", []any{123}).Return()
+				logger.On("Errorf", "[%d] failed to record migration as done; err=%s", mock.Anything).Return()
+				return logger
+			},
+			wantPhase:   MigrationPhaseSaveStatus,
+			wantNotDone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.db(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+				},
+				logger:  tt.logger(),
+				lockTTL: DefaultLockTTL,
+			}
+
+			err := e.Execute(context.Background())
+
+			var migrationErr *MigrationError
+			if !errors.As(err, &migrationErr) {
+				t.Fatalf("Executor.Execute() error = %v, want *MigrationError", err)
+			}
+
+			if migrationErr.ID != 123 || migrationErr.Phase != tt.wantPhase {
+				t.Errorf("Executor.Execute() error = %v, want phase %v", err, tt.wantPhase)
+			}
+
+			if errors.Is(err, ErrMigrationNotRecorded) != tt.wantNotDone {
+				t.Errorf("Executor.Execute() error = %v, want ErrMigrationNotRecorded %v", err, tt.wantNotDone)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_checksumMismatch(t *testing.T) {
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+	mismatch := "checksum mismatch: 123_name.yaml: recorded old, current " + checksum
+
+	tests := []struct {
+		name    string
+		allow   bool
+		db      func() driver.Database
+		logger  func() Logger
+		wantErr error
+	}{
+		{
+			name: "fail on changed migration",
+			db: func() driver.Database {
+				ctx := mock.Anything
+
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				coll.On("DocumentExists", ctx, "123").Return(true, nil)
+				coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+					Key:      "123",
+					Checksum: "old",
+					Status:   MigrationStatusDone,
+				}, driver.DocumentMeta{}, nil)
+
+				db := new(MockArangoDB)
+				db.On("Collection", ctx, "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+				logger.On("Errorf", "[%d] migration changed since it was executed; err=%s", []any{123, mismatch}).Return()
+				return logger
+			},
+			wantErr: ErrChecksumMismatch,
+		},
+		{
+			name:  "update checksum of changed migration",
+			allow: true,
+			db: func() driver.Database {
+				ctx := mock.Anything
+
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				coll.On("DocumentExists", ctx, "123").Return(true, nil)
+				coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+					Key:      "123",
+					Checksum: "old",
+					Status:   MigrationStatusDone,
+				}, driver.DocumentMeta{}, nil)
+				coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+					return item.Checksum == checksum && item.Status == MigrationStatusDone
+				})).Return(driver.DocumentMeta{}, nil)
+
+				db := new(MockArangoDB)
+				db.On("Collection", ctx, "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+				logger.On("Infof", "[%d] migration changed since it was executed, updating the recorded checksum; %s", []any{123, mismatch}).Return()
+				logger.On("Infof", "[%d] migration already executed", []any{123}).Return()
+				logger.On("Info", []any{"all migrations executed successfully"}).Return()
+				return logger
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.db(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID:   123,
+						Path: "123_name.yaml",
+					},
+				},
+				logger:              tt.logger(),
+				lockTTL:             DefaultLockTTL,
+				allowChecksumChange: tt.allow,
+			}
+
+			if err := e.Execute(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Executor.Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_legacyRecordDrift(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+	// The first execution after upgrading re-keys the record created by an
+	// earlier version, which is keyed by the checksum of the migration.
+	var record *MigrationItem
+
+	legacy := new(MockArangoCollection)
+	mockLock(legacy)
+	legacy.On("DocumentExists", ctx, "123").Return(false, nil)
+	legacy.On("DocumentExists", ctx, checksum).Return(true, nil)
+	legacy.On("ReadDocument", ctx, checksum, mock.Anything).Return(&MigrationItem{
+		Key:    checksum,
+		Name:   "123_name",
+		Status: MigrationStatusDone,
+	}, driver.DocumentMeta{}, nil)
+	legacy.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Run(func(args mock.Arguments) {
+		record = args.Get(1).(*MigrationItem)
+	}).Return(driver.DocumentMeta{}, nil).Once()
+	legacy.On("RemoveDocument", ctx, checksum).Return(driver.DocumentMeta{}, nil).Once()
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(legacy, nil)
+
+	logger := new(MockLogger)
+	logger.On("Infof", mock.Anything, mock.Anything).Return()
+	logger.On("Info", mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	migration := &Migration{
+		ID:   123,
+		Path: "123_name.yaml",
+	}
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{migration},
+		logger:     logger,
+		lockTTL:    DefaultLockTTL,
+	}
+
+	if err := e.Execute(context.Background()); err != nil {
+		t.Fatalf("Executor.Execute() error = %v", err)
+	}
+
+	legacy.AssertExpectations(t)
+	if record == nil || record.Key != "123" || record.MigrationID != 123 || record.Checksum != checksum {
+		t.Fatalf("re-keyed record = %+v, want key 123 with checksum %s", record, checksum)
+	}
+
+	// Once re-keyed, editing the migration file is detected as a drift
+	// instead of executing the migration again.
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, "123").Return(true, nil)
+	coll.On("ReadDocument", ctx, "123", mock.Anything).Return(record, driver.DocumentMeta{}, nil)
+
+	db = new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+
+	e.db = db
+	migration.Operations = []*Operation{
+		{
+			Kind:       OperationKindCollectionCreate,
+			Collection: "users",
+		},
+	}
+
+	if err := e.Execute(context.Background()); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Executor.Execute() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+
+	db.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExecutor_Execute_legacyRecordDriftBeforeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+
+	// The migration file was edited before upgrading, so neither its key nor
+	// its checksum matches the record created by an earlier version.
+	var record *MigrationItem
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+	mockLegacyRecords(coll, &MigrationItem{
+		Key:    "old",
+		Name:   "123_name",
+		Status: MigrationStatusDone,
+	})
+	coll.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Run(func(args mock.Arguments) {
+		record = args.Get(1).(*MigrationItem)
+	}).Return(driver.DocumentMeta{}, nil).Once()
+	coll.On("RemoveDocument", ctx, "old").Return(driver.DocumentMeta{}, nil).Once()
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+
+	logger := new(MockLogger)
+	logger.On("Infof", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{
+				ID:   123,
+				Path: "123_name.yaml",
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+					},
+				},
+			},
+		},
+		logger:  logger,
+		lockTTL: DefaultLockTTL,
+	}
+
+	if err := e.Execute(context.Background()); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Executor.Execute() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+
+	coll.AssertExpectations(t)
+	if record == nil || record.Key != "123" || record.Checksum != "old" || record.Status != MigrationStatusDone {
+		t.Errorf("re-keyed record = %+v, want key 123 with checksum old", record)
+	}
+
+	db.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExecutor_Repair(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, "123").Return(true, nil)
+	coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+		Key:      "123",
+		Checksum: "old",
+		Status:   MigrationStatusDone,
+	}, driver.DocumentMeta{}, nil)
+	coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+	mockLegacyRecords(coll)
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Checksum == checksum && item.Status == MigrationStatusDone
+	})).Return(driver.DocumentMeta{}, nil).Once()
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+
+	logger := new(MockLogger)
+	logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+	logger.On("Infof", "[%d] fetching migration status", mock.Anything).Return()
+	logger.On("Infof", "[%d] updating the recorded checksum; %s", mock.Anything).Return()
+	logger.On("Info", []any{"all migrations repaired successfully"}).Return()
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{
+				ID:   123,
+				Path: "123_name.yaml",
+			},
+			{
+				ID:   124,
+				Path: "124_name.yaml",
+			},
+		},
+		logger:  logger,
+		lockTTL: DefaultLockTTL,
+	}
+
+	if err := e.Repair(context.Background()); err != nil {
+		t.Fatalf("Executor.Repair() error = %v", err)
+	}
+
+	coll.AssertExpectations(t)
+}
+
+func TestExecutor_Retry(t *testing.T) {
+	tests := []struct {
+		name   string
+		status MigrationStatus
+	}{
+		{
+			name:   "resume failed migration",
+			status: MigrationStatusFailed,
+		},
+		{
+			name:   "resume interrupted migration",
+			status: MigrationStatusRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := mock.Anything
+
+			coll := new(MockArangoCollection)
+			mockLock(coll)
+			coll.On("DocumentExists", ctx, "123").Return(true, nil)
+			coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+				Key:                 "123",
+				Status:              tt.status,
+				CompletedOperations: []int{0},
+			}, driver.DocumentMeta{}, nil)
+			coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+				return item.Status == MigrationStatusRunning && reflect.DeepEqual(item.CompletedOperations, []int{0})
+			})).Return(driver.DocumentMeta{}, nil).Once()
+			coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+				return item.Status == MigrationStatusRunning && reflect.DeepEqual(item.CompletedOperations, []int{0, 1})
+			})).Return(driver.DocumentMeta{}, nil).Once()
+			coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+				return item.Status == MigrationStatusDone &&
+					reflect.DeepEqual(item.CompletedOperations, []int{0, 1}) &&
+					item.Error == "" &&
+					item.AppliedAt.Equal(item.FinishedAt) &&
+					item.Duration == item.FinishedAt.Sub(item.StartedAt)
+			})).Return(driver.DocumentMeta{}, nil).Once()
+
+			db := new(MockArangoDB)
+			db.On("Collection", ctx, "test").Return(coll, nil)
+			db.On("CreateCollection", ctx, "posts", mock.Anything).Return(coll, nil).Once()
+
+			logger := new(MockLogger)
+			logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+			logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+			logger.On("Infof", "[%d] resuming migration after %d completed operations", []any{123, 1}).Return()
+			logger.On("Infof", "[%d] migration executed successfully", []any{123}).Return()
+			logger.On("Info", []any{"all migrations executed successfully"}).Return()
+
+			e := &Executor{
+				db:         db,
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+						Operations: []*Operation{
+							{
+								Kind:       OperationKindCollectionCreate,
+								Collection: "users",
+							},
+							{
+								Kind:       OperationKindCollectionCreate,
+								Collection: "posts",
+							},
+						},
+					},
+				},
+				logger:  logger,
+				lockTTL: DefaultLockTTL,
+			}
+
+			if err := e.Retry(context.Background()); err != nil {
+				t.Fatalf("Executor.Retry() error = %v", err)
+			}
+
+			db.AssertExpectations(t)
+			coll.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExecutor_Retry_partialRollback(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, "123").Return(true, nil)
+	coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+		Key:    "123",
+		Status: MigrationStatusRollbackFailed,
+	}, driver.DocumentMeta{}, nil)
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+
+	logger := new(MockLogger)
+	logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+	logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+	logger.On("Errorf", "[%d] migration is partially rolled back, roll it back again", []any{123}).Return()
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{
+				ID: 123,
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+					},
+				},
+			},
+		},
+		logger:  logger,
+		lockTTL: DefaultLockTTL,
+	}
+
+	if err := e.Retry(context.Background()); !errors.Is(err, ErrRollbackFailed) {
+		t.Fatalf("Executor.Retry() error = %v, want %v", err, ErrRollbackFailed)
+	}
+
+	db.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything, mock.Anything)
+	logger.AssertExpectations(t)
+}
+
+func TestExecutor_Retry_resumesBackfill(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+	query := "FOR doc IN users FILTER doc._key > @lastKey SORT doc._key LIMIT @batch UPDATE doc WITH { active: true } IN users RETURN OLD._key"
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, "123").Return(true, nil)
+	coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+		Key:         "123",
+		Status:      MigrationStatusFailed,
+		Checkpoints: map[int]string{0: "2"},
+	}, driver.DocumentMeta{}, nil)
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Status == MigrationStatusRunning && reflect.DeepEqual(item.Checkpoints, map[int]string{0: "2"})
+	})).Return(driver.DocumentMeta{}, nil).Once()
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Status == MigrationStatusRunning && reflect.DeepEqual(item.Checkpoints, map[int]string{0: "3"})
+	})).Return(driver.DocumentMeta{}, nil).Once()
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Status == MigrationStatusRunning && reflect.DeepEqual(item.CompletedOperations, []int{0}) && len(item.Checkpoints) == 0
+	})).Return(driver.DocumentMeta{}, nil).Once()
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Status == MigrationStatusDone
+	})).Return(driver.DocumentMeta{}, nil).Once()
+
+	cursor := new(MockArangoCursor)
+	cursor.On("ReadDocument", mock.Anything, mock.Anything).Return("3", driver.DocumentMeta{}, nil).Once()
+	cursor.On("ReadDocument", mock.Anything, mock.Anything).Return(nil, driver.DocumentMeta{}, driver.NoMoreDocumentsError{})
+	cursor.On("Close").Return(nil)
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+	db.On("Query", mock.Anything, query, map[string]any{"batch": 2, "lastKey": "2"}).Return(cursor, nil).Once()
+
+	logger := new(MockLogger)
+	logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+	logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+	logger.On("Infof", "[%d] resuming migration after %d completed operations", []any{123, 0}).Return()
+	logger.On("Infof", "resuming backfill of %s after key %q", []any{"users", "2"}).Return().Once()
+	logger.On("Infof", "backfill of %s processed %d documents, last key %q", []any{"users", 1, "3"}).Return().Once()
+	logger.On("Infof", "[%d] migration executed successfully", []any{123}).Return()
+	logger.On("Info", []any{"all migrations executed successfully"}).Return()
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{
+				ID: 123,
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindAQLBackfill,
+						Collection: "users",
+						Options: map[string]any{
+							"query":     query,
+							"batchSize": 2,
+						},
+					},
+				},
+			},
+		},
+		logger:  logger,
+		lockTTL: DefaultLockTTL,
+	}
+
+	if err := e.Retry(context.Background()); err != nil {
+		t.Fatalf("Executor.Retry() error = %v", err)
+	}
+
+	db.AssertExpectations(t)
+	coll.AssertExpectations(t)
+	logger.AssertExpectations(t)
+}
+
+func TestExecutor_Execute_recordsCompletedOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil).Times(3)
+	coll.On("DocumentExists", ctx, "123").Return(true, nil)
+	coll.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Return(driver.DocumentMeta{}, nil)
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Status == MigrationStatusRunning && reflect.DeepEqual(item.CompletedOperations, []int{0})
+	})).Return(driver.DocumentMeta{}, nil).Once()
+	coll.On("UpdateDocument", ctx, "123", mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Status == MigrationStatusFailed &&
+			reflect.DeepEqual(item.CompletedOperations, []int{0}) &&
+			item.Error == "error" &&
+			item.Version == "v1.0.0" &&
+			item.OperationCount == 2 &&
+			item.AppliedAt.IsZero() &&
+			!item.StartedAt.IsZero() &&
+			!item.FinishedAt.IsZero()
+	})).Return(driver.DocumentMeta{}, nil).Once()
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+	db.On("CreateCollection", ctx, "users", mock.Anything).Return(coll, nil)
+	db.On("CreateCollection", ctx, "posts", mock.Anything).Return(coll, fmt.Errorf("error"))
+
+	logger := new(MockLogger)
+	logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+	logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+	logger.On("Infof", "[%d] executing migration", []any{123}).Return()
+	logger.On("Errorf", "[%d] migration failed; err=%s", []any{123, "migration failed: error"}).Return()
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{
+				ID: 123,
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+					},
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "posts",
+					},
+				},
+			},
+		},
+		logger:  logger,
+		lockTTL: DefaultLockTTL,
+	}
+
+	e.version = "v1.0.0"
+
+	if err := e.Execute(context.Background()); err == nil || err.Error() != "migration failed: error" {
+		t.Fatalf("Executor.Execute() error = %v, want migration failed: error", err)
+	}
+
+	coll.AssertExpectations(t)
+}
+
+func TestExecutor_ExecuteTo(t *testing.T) {
+	type args struct {
+		target string
+	}
+	tests := []struct {
+		name    string
+		db      func() driver.Database
+		logger  func() Logger
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "execute to migration",
+			db: func() driver.Database {
+				ctx := mock.Anything
+
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+				mockLegacyRecords(coll)
+				coll.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Return(driver.DocumentMeta{}, nil)
+
+				db := new(MockArangoDB)
+				db.On("Collection", ctx, "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+				logger.On("Infof", "[%d] executing migration", []any{123}).Return()
+				logger.On("Infof", "[%d] migration executed successfully", []any{123}).Return()
+				logger.On("Infof", "all migrations executed successfully up to %s", []any{"123"}).Return()
+				logger.On("Infof", "[%d] fetching migration status", []any{124}).Return()
+				logger.On("Infof", "[%d] migration left pending", []any{124}).Return()
+				logger.On("Infof", "%d migrations left pending", []any{1}).Return()
+				return logger
+			},
+			args: args{
+				target: "123",
+			},
+		},
+		{
+			name: "execute to unknown migration",
+			db: func() driver.Database {
+				return new(MockArangoDB)
+			},
+			logger: func() Logger {
+				return new(MockLogger)
+			},
+			args: args{
+				target: "999",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := tt.logger()
+			e := &Executor{
+				db:         tt.db(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+					{
+						ID: 124,
+					},
+				},
+				logger:  logger,
+				lockTTL: DefaultLockTTL,
+			}
+
+			if err := e.ExecuteTo(context.Background(), tt.args.target); (err != nil) != tt.wantErr {
+				t.Errorf("Executor.ExecuteTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			logger.(*MockLogger).AssertExpectations(t)
+		})
+	}
+}
+
+func TestExecutor_Execute_outOfOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := mock.Anything
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("DocumentExists", ctx, "124").Return(true, nil)
+	coll.On("ReadDocument", ctx, "124", mock.Anything).Return(&MigrationItem{
+		Key:    "124",
+		Status: MigrationStatusDone,
+	}, driver.DocumentMeta{}, nil)
+	coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+	mockLegacyRecords(coll)
+
+	db := new(MockArangoDB)
+	db.On("Collection", ctx, "test").Return(coll, nil)
+
+	want := "out of order migration: 123_create_users.yaml sorts before the applied 124_create_posts.yaml"
+
+	logger := new(MockLogger)
+	logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+	logger.On("Infof", "[%d] fetching migration status", mock.Anything).Return()
+	logger.On("Errorf", "[%d] migration is out of order; err=%s", []any{123, want}).Return()
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{
+				ID:   123,
+				Path: "123_create_users.yaml",
+			},
+			{
+				ID:   124,
+				Path: "124_create_posts.yaml",
+			},
+		},
+		logger:           logger,
+		lockTTL:          DefaultLockTTL,
+		outOfOrderPolicy: OutOfOrderFail,
+	}
+
+	err := e.Execute(context.Background())
+	if !errors.Is(err, ErrOutOfOrderMigration) || err.Error() != want {
+		t.Errorf("Executor.Execute() error = %v, want %v", err, want)
+	}
+
+	coll.AssertNotCalled(t, "CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem"))
+}
@@ -0,0 +1,145 @@
+package arangom
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidGoMigration is returned when a Go migration cannot be registered.
+var ErrInvalidGoMigration = fmt.Errorf("invalid Go migration")
+
+// registeredMigrations are the Go migrations registered by Register.
+var registeredMigrations = new(registry)
+
+// RegisterOption is a function that sets configuration options of a Go
+// migration.
+type RegisterOption func(*goMigration) error
+
+// WithMigrationVersion sets the version of a Go migration. The checksum of a
+// Go migration is derived from its ID and version, therefore the version must
+// be changed whenever the functions of an applied migration change.
+func WithMigrationVersion(version string) RegisterOption {
+	return func(m *goMigration) error {
+		m.version = version
+		return nil
+	}
+}
+
+// Register registers a Go migration, which is loaded by LoadMigrations along
+// with the migration files. The name of the migration takes the place of its
+// file name, therefore it is used to order the migration. The up function
+// executes the migration, while the optional down function rolls it back. Go
+// migrations are meant to be registered from init functions, so Register
+// panics if the migration is invalid or its ID is already registered.
+func Register(id int, name string, up, down OperationFn, opts ...RegisterOption) {
+	m := &goMigration{
+		id:   id,
+		name: name,
+		up:   up,
+		down: down,
+	}
+
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := registeredMigrations.register(m); err != nil {
+		panic(err)
+	}
+}
+
+// RegisteredMigrations returns the registered Go migrations in the order they
+// were registered. Every call returns new migrations.
+func RegisteredMigrations() []*Migration {
+	return registeredMigrations.migrations()
+}
+
+// goMigration is a migration registered as Go functions.
+type goMigration struct {
+	id      int
+	name    string
+	version string
+	up      OperationFn
+	down    OperationFn
+}
+
+// migration returns a new migration running the functions of the Go
+// migration. The version of the migration is stored in the options of its
+// operation, making it part of the checksum.
+func (m *goMigration) migration() *Migration {
+	migration := &Migration{
+		ID:   m.id,
+		Path: m.name,
+		Operations: []*Operation{
+			{
+				Kind:    OperationKindGo,
+				Options: map[string]any{"version": m.version},
+				fn:      m.up,
+			},
+		},
+	}
+
+	if m.down != nil {
+		migration.Down = []*Operation{
+			{
+				Kind:    OperationKindGo,
+				Options: map[string]any{"version": m.version},
+				fn:      m.down,
+			},
+		}
+	}
+
+	return migration
+}
+
+// key returns the key of the Go migration in the migration collection.
+func (m *goMigration) key() string {
+	return m.migration().Key()
+}
+
+// registry is a set of Go migrations.
+type registry struct {
+	mu    sync.Mutex
+	items []*goMigration
+}
+
+// register adds the Go migration to the registry. If a registered migration
+// has the same ID, or the same name if neither has an ID, an error wrapping
+// ErrDuplicateMigrationID is returned.
+func (r *registry) register(m *goMigration) error {
+	if m.name == "" {
+		return fmt.Errorf("%w: %d: missing name", ErrInvalidGoMigration, m.id)
+	}
+
+	if m.up == nil {
+		return fmt.Errorf("%w: %s: missing up function", ErrInvalidGoMigration, m.name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range r.items {
+		if item.key() == m.key() {
+			return fmt.Errorf("%w: %s: %s and %s", ErrDuplicateMigrationID, m.key(), item.name, m.name)
+		}
+	}
+
+	r.items = append(r.items, m)
+
+	return nil
+}
+
+// migrations returns new migrations of the registered Go migrations.
+func (r *registry) migrations() []*Migration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	migrations := make([]*Migration, 0, len(r.items))
+	for _, item := range r.items {
+		migrations = append(migrations, item.migration())
+	}
+
+	return migrations
+}
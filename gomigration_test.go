@@ -0,0 +1,150 @@
+package arangom
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arangodb/go-driver"
+)
+
+func TestRegistry_register(t *testing.T) {
+	noop := func(_ context.Context, _ driver.Database) error {
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		registered []*goMigration
+		migration  *goMigration
+		wantErr    error
+	}{
+		{
+			name:      "register migration",
+			migration: &goMigration{id: 123, name: "123_backfill_users", up: noop},
+		},
+		{
+			name: "register migration with other ID",
+			registered: []*goMigration{
+				{id: 123, name: "123_backfill_users", up: noop},
+			},
+			migration: &goMigration{id: 124, name: "124_backfill_posts", up: noop, down: noop},
+		},
+		{
+			name:      "register migration without name",
+			migration: &goMigration{id: 123, up: noop},
+			wantErr:   ErrInvalidGoMigration,
+		},
+		{
+			name:      "register migration without up function",
+			migration: &goMigration{id: 123, name: "123_backfill_users", down: noop},
+			wantErr:   ErrInvalidGoMigration,
+		},
+		{
+			name: "register migration with duplicate ID",
+			registered: []*goMigration{
+				{id: 123, name: "123_backfill_users", up: noop},
+			},
+			migration: &goMigration{id: 123, name: "123_backfill_posts", up: noop},
+			wantErr:   ErrDuplicateMigrationID,
+		},
+		{
+			name: "register migration with duplicate name",
+			registered: []*goMigration{
+				{name: "backfill_users", up: noop},
+			},
+			migration: &goMigration{name: "backfill_users", up: noop},
+			wantErr:   ErrDuplicateMigrationID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &registry{items: tt.registered}
+
+			err := r.register(tt.migration)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("registry.register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			want := len(tt.registered)
+			if tt.wantErr == nil {
+				want++
+			}
+
+			if got := len(r.migrations()); got != want {
+				t.Errorf("registry.migrations() returned %d migrations, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestGoMigration_migration(t *testing.T) {
+	ctx := context.Background()
+	db := new(MockArangoDB)
+
+	var calls []string
+	up := func(_ context.Context, _ driver.Database) error {
+		calls = append(calls, "up")
+		return nil
+	}
+	down := func(_ context.Context, _ driver.Database) error {
+		calls = append(calls, "down")
+		return nil
+	}
+
+	migration := (&goMigration{id: 123, name: "123_backfill_users", version: "1", up: up, down: down}).migration()
+
+	if migration.Name() != "123_backfill_users" || migration.Key() != "123" {
+		t.Errorf("migration name = %s, key = %s, want 123_backfill_users, 123", migration.Name(), migration.Key())
+	}
+
+	if err := migration.Migrate(ctx, db); err != nil {
+		t.Fatalf("Migration.Migrate() error = %v", err)
+	}
+
+	if err := migration.Rollback(ctx, db); err != nil {
+		t.Fatalf("Migration.Rollback() error = %v", err)
+	}
+
+	if len(calls) != 2 || calls[0] != "up" || calls[1] != "down" {
+		t.Errorf("calls = %v, want [up down]", calls)
+	}
+
+	irreversible := (&goMigration{id: 123, name: "123_backfill_users", up: up}).migration()
+	if err := irreversible.Rollback(ctx, db); !errors.Is(err, ErrIrreversibleMigration) {
+		t.Errorf("Migration.Rollback() error = %v, want %v", err, ErrIrreversibleMigration)
+	}
+}
+
+func TestGoMigration_checksum(t *testing.T) {
+	checksum := func(m *goMigration) string {
+		t.Helper()
+
+		checksum, err := m.migration().Checksum()
+		if err != nil {
+			t.Fatalf("Migration.Checksum() error = %v", err)
+		}
+
+		return checksum
+	}
+
+	up := func(_ context.Context, _ driver.Database) error {
+		return nil
+	}
+	otherUp := func(_ context.Context, _ driver.Database) error {
+		return errors.New("changed")
+	}
+
+	v1 := checksum(&goMigration{id: 123, name: "123_backfill_users", version: "1", up: up})
+
+	if got := checksum(&goMigration{id: 123, name: "123_backfill_users", version: "1", up: otherUp}); got != v1 {
+		t.Errorf("checksum of the same version = %s, want %s", got, v1)
+	}
+
+	if got := checksum(&goMigration{id: 123, name: "123_backfill_users", version: "2", up: up}); got == v1 {
+		t.Errorf("checksum of an other version = %s, want it to differ", got)
+	}
+}
@@ -0,0 +1,77 @@
+package arangom
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"os/user"
+	"runtime/debug"
+	"slices"
+)
+
+// modulePath is the path of the arangom module.
+const modulePath = "github.com/gabor-boros/arangom"
+
+// History returns the migration items of the migration collection ordered by
+// the time the migrations were applied. Migrations that were never applied
+// successfully come first, ordered by the start of their latest execution.
+func (e *Executor) History(ctx context.Context) ([]*MigrationItem, error) {
+	items, err := e.fetchMigrationItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*MigrationItem, 0, len(items))
+	for _, item := range items {
+		history = append(history, item)
+	}
+
+	slices.SortFunc(history, func(a, b *MigrationItem) int {
+		return cmp.Or(
+			a.AppliedAt.Compare(b.AppliedAt),
+			a.StartedAt.Compare(b.StartedAt),
+			cmp.Compare(a.MigrationID, b.MigrationID),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+
+	return history, nil
+}
+
+// buildVersion returns the version of the arangom module the binary is built
+// with, or "dev" if it is unknown.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return dep.Version
+		}
+	}
+
+	return "dev"
+}
+
+// currentHostname returns the name of the host, or an empty string if it is
+// unknown.
+func currentHostname() string {
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// currentUser returns the name of the current user, or an empty string if it
+// is unknown.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
@@ -0,0 +1,66 @@
+package arangom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExecutor_History(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	startedAt := time.Date(2023, 2, 28, 6, 47, 43, 0, time.UTC)
+
+	cursor := mockCursor(
+		&MigrationItem{
+			Key:         "125",
+			MigrationID: 125,
+			Status:      MigrationStatusDone,
+			StartedAt:   startedAt.Add(2 * time.Hour),
+			AppliedAt:   startedAt.Add(2 * time.Hour),
+		},
+		&MigrationItem{
+			Key:         "124",
+			MigrationID: 124,
+			Status:      MigrationStatusFailed,
+			StartedAt:   startedAt.Add(3 * time.Hour),
+			Error:       "error",
+		},
+		&MigrationItem{
+			Key:         "123",
+			MigrationID: 123,
+			Status:      MigrationStatusDone,
+			StartedAt:   startedAt,
+			AppliedAt:   startedAt.Add(time.Hour),
+		},
+	)
+
+	db := new(MockArangoDB)
+	db.On("CollectionExists", ctx, "test").Return(true, nil)
+	db.On("Query", ctx, migrationItemsQuery, mock.Anything).Return(cursor, nil)
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		logger:     new(MockLogger),
+	}
+
+	got, err := e.History(ctx)
+	if err != nil {
+		t.Fatalf("Executor.History() error = %v", err)
+	}
+
+	want := []string{"124", "123", "125"}
+	if len(got) != len(want) {
+		t.Fatalf("Executor.History() = %v, want %v", got, want)
+	}
+
+	for i, item := range got {
+		if item.Key != want[i] {
+			t.Errorf("Executor.History()[%d] = %v, want %v", i, item.Key, want[i])
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package arangom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrUndefinedVariable is returned when a migration file refers to a
+	// variable that is not defined and has no default value.
+	ErrUndefinedVariable = fmt.Errorf("undefined variable")
+	// ErrInvalidVariable is returned when a variable reference of a migration
+	// file is malformed.
+	ErrInvalidVariable = fmt.Errorf("invalid variable")
+)
+
+// WithVariables sets the variables substituted in the migration files. The
+// variables take precedence over the environment variables of the same name.
+func WithVariables(variables map[string]string) LoadOption {
+	return func(o *loadOptions) error {
+		if o.variables == nil {
+			o.variables = make(map[string]string, len(variables))
+		}
+
+		maps.Copy(o.variables, variables)
+
+		return nil
+	}
+}
+
+// lookupVariable returns the value of the variable, looking up the variables
+// set by WithVariables first, then the environment variables.
+func (o *loadOptions) lookupVariable(name string) (string, bool) {
+	if value, ok := o.variables[name]; ok {
+		return value, true
+	}
+
+	return os.LookupEnv(name)
+}
+
+// interpolate substitutes the ${NAME} and ${NAME:-default} references of the
+// migration file with the value of the variable. The default value is used if
+// the variable is undefined or empty, while "$${" is kept as a literal "${".
+// Every malformed or undefined reference is reported as a SchemaError.
+func interpolate(name string, b []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	out, _, err := substitute(name, b, lookup)
+	return out, err
+}
+
+// substitution is the byte range of a substituted value in the output of
+// substitute.
+type substitution struct {
+	start, end int
+}
+
+// substitute substitutes the variable references like interpolate, returning
+// the byte ranges of the substituted values in the output as well.
+func substitute(name string, b []byte, lookup func(string) (string, bool)) ([]byte, []substitution, error) {
+	var (
+		out           bytes.Buffer
+		substitutions []substitution
+		errs          []error
+	)
+
+	line, column := 1, 1
+	advance := func(span []byte) {
+		for _, c := range span {
+			if c == '\n' {
+				line, column = line+1, 1
+			} else {
+				column++
+			}
+		}
+	}
+
+	for i := 0; i < len(b); {
+		switch {
+		case bytes.HasPrefix(b[i:], []byte("$${")):
+			out.WriteString("${")
+			advance(b[i : i+3])
+			i += 3
+		case bytes.HasPrefix(b[i:], []byte("${")):
+			end := bytes.IndexByte(b[i+2:], '}')
+			if end < 0 {
+				errs = append(errs, &SchemaError{Path: name, Line: line, Column: column, Err: fmt.Errorf("%w: unterminated reference", ErrInvalidVariable)})
+				out.Write(b[i:])
+				i = len(b)
+
+				continue
+			}
+
+			reference := b[i : i+2+end+1]
+			variable, fallback, hasFallback := strings.Cut(string(reference[2:len(reference)-1]), ":-")
+
+			value, ok := lookup(variable)
+			switch {
+			case !isVariableName(variable):
+				errs = append(errs, &SchemaError{Path: name, Line: line, Column: column, Err: fmt.Errorf("%w: %q", ErrInvalidVariable, string(reference))})
+			case hasFallback && (!ok || value == ""):
+				value = fallback
+			case !ok:
+				errs = append(errs, &SchemaError{Path: name, Line: line, Column: column, Err: fmt.Errorf("%w: %s", ErrUndefinedVariable, variable)})
+			}
+
+			substitutions = append(substitutions, substitution{start: out.Len(), end: out.Len() + len(value)})
+			out.WriteString(value)
+			advance(reference)
+			i += len(reference)
+		default:
+			out.WriteByte(b[i])
+			advance(b[i : i+1])
+			i++
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return out.Bytes(), substitutions, nil
+}
+
+// isVariableName returns true if the name is a valid variable name, consisting
+// of letters, digits and underscores, not starting with a digit.
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,232 @@
+package arangom
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInterpolate(t *testing.T) {
+	variables := map[string]string{
+		"SHARDS": "3",
+		"EMPTY":  "",
+	}
+
+	lookup := func(name string) (string, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		want     string
+		wantErrs []string
+		wantIs   error
+	}{
+		{
+			name: "substitute variable",
+			data: "numberOfShards: ${SHARDS}\n",
+			want: "numberOfShards: 3\n",
+		},
+		{
+			name: "substitute default value",
+			data: "replicationFactor: ${REPLICATION_FACTOR:-2}\nwriteConcern: ${EMPTY:-1}\n",
+			want: "replicationFactor: 2\nwriteConcern: 1\n",
+		},
+		{
+			name: "substitute defined variable with default value",
+			data: "numberOfShards: ${SHARDS:-1}\n",
+			want: "numberOfShards: 3\n",
+		},
+		{
+			name: "keep escaped reference",
+			data: "query: RETURN \"$${SHARDS}\"\n",
+			want: "query: RETURN \"${SHARDS}\"\n",
+		},
+		{
+			name: "keep dollar signs",
+			data: "query: RETURN \"$SHARDS costs 5$\"\n",
+			want: "query: RETURN \"$SHARDS costs 5$\"\n",
+		},
+		{
+			name:     "undefined variables",
+			data:     "id: 1\nnumberOfShards: ${SHARDS} ${UNDEFINED}\nexpireAfter: ${TTL}\n",
+			wantErrs: []string{"migration.yaml:2:27: undefined variable: UNDEFINED", "migration.yaml:3:14: undefined variable: TTL"},
+			wantIs:   ErrUndefinedVariable,
+		},
+		{
+			name:     "invalid variable name",
+			data:     "numberOfShards: ${1SHARDS}\n",
+			wantErrs: []string{`migration.yaml:1:17: invalid variable: "${1SHARDS}"`},
+			wantIs:   ErrInvalidVariable,
+		},
+		{
+			name:     "unterminated reference",
+			data:     "numberOfShards: ${SHARDS\n",
+			wantErrs: []string{"migration.yaml:1:17: invalid variable: unterminated reference"},
+			wantIs:   ErrInvalidVariable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := interpolate("migration.yaml", []byte(tt.data), lookup)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("interpolate() error = %v", err)
+				}
+
+				if string(got) != tt.want {
+					t.Errorf("interpolate() = %q, want %q", got, tt.want)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, tt.wantIs) {
+				t.Fatalf("interpolate() error = %v, want %v", err, tt.wantIs)
+			}
+
+			errs := err.(interface{ Unwrap() []error }).Unwrap()
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("interpolate() error = %v, want %v", err, tt.wantErrs)
+			}
+
+			for i, err := range errs {
+				if err.Error() != tt.wantErrs[i] {
+					t.Errorf("interpolate() error #%d = %v, want %v", i, err, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigration_variables(t *testing.T) {
+	fsys := fstest.MapFS{
+		"123_create_users.yaml": {
+			Data: []byte("id: 123\noperations:\n  - kind: createCollection\n    collection: users\n    options:\n      numberOfShards: ${SHARDS:-1}\n"),
+		},
+		"124_create_posts.yaml": {
+			Data: []byte("id: ${ID}\n"),
+		},
+	}
+
+	dev, err := LoadMigration(fsys, "123_create_users.yaml")
+	if err != nil {
+		t.Fatalf("LoadMigration() error = %v", err)
+	}
+
+	prod, err := LoadMigration(fsys, "123_create_users.yaml", WithVariables(map[string]string{"SHARDS": "9"}))
+	if err != nil {
+		t.Fatalf("LoadMigration() error = %v", err)
+	}
+
+	if got := dev.Operations[0].Options["numberOfShards"]; got != 1 {
+		t.Errorf("numberOfShards = %v, want 1", got)
+	}
+
+	if got := prod.Operations[0].Options["numberOfShards"]; got != 9 {
+		t.Errorf("numberOfShards = %v, want 9", got)
+	}
+
+	devChecksum, _ := dev.Checksum()
+	prodChecksum, _ := prod.Checksum()
+	if devChecksum != prodChecksum {
+		t.Errorf("Migration.Checksum() = %s and %s, want them to be equal", devChecksum, prodChecksum)
+	}
+
+	if _, err := LoadMigration(fsys, "124_create_posts.yaml", WithVariables(map[string]string{"ID": "124"})); !errors.Is(err, ErrInvalidVariable) {
+		t.Errorf("LoadMigration() error = %v, want %v", err, ErrInvalidVariable)
+	}
+}
+
+func TestLoadMigration_variablesInFlowStyle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"125_create_users.json": {
+			Data: []byte(`{"id": 125, "operations": [{"kind": "createCollection", "collection": "users", "options": {"replicationFactor": ${VALUE}}}]}`),
+		},
+		"126_create_users.yaml": {
+			Data: []byte("id: 126\noperations:\n  - kind: createCollection\n    collection: users\n    options: {numberOfShards: ${VALUE}}\n"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		option string
+	}{
+		{name: "125_create_users.json", option: "replicationFactor"},
+		{name: "126_create_users.yaml", option: "numberOfShards"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dev, err := LoadMigration(fsys, tt.name, WithVariables(map[string]string{"VALUE": "1"}))
+			if err != nil {
+				t.Fatalf("LoadMigration() error = %v", err)
+			}
+
+			prod, err := LoadMigration(fsys, tt.name, WithVariables(map[string]string{"VALUE": "3"}))
+			if err != nil {
+				t.Fatalf("LoadMigration() error = %v", err)
+			}
+
+			if got := prod.Operations[0].Options[tt.option]; got != 3 {
+				t.Errorf("%s = %v, want 3", tt.option, got)
+			}
+
+			if _, err := prod.Operations[0].ResolveOptions(); err != nil {
+				t.Errorf("Operation.ResolveOptions() error = %v", err)
+			}
+
+			devChecksum, err := dev.Checksum()
+			if err != nil {
+				t.Fatalf("Migration.Checksum() error = %v", err)
+			}
+
+			prodChecksum, err := prod.Checksum()
+			if err != nil {
+				t.Fatalf("Migration.Checksum() error = %v", err)
+			}
+
+			if devChecksum != prodChecksum {
+				t.Errorf("Migration.Checksum() = %s and %s, want them to be equal", devChecksum, prodChecksum)
+			}
+		})
+	}
+}
+
+func TestLoadOptions_lookupVariable(t *testing.T) {
+	t.Setenv("ARANGOM_TEST_SHARDS", "3")
+	t.Setenv("ARANGOM_TEST_REPLICATION_FACTOR", "2")
+
+	options, err := newLoadOptions([]LoadOption{
+		WithVariables(map[string]string{"ARANGOM_TEST_SHARDS": "9"}),
+		WithVariables(map[string]string{"ARANGOM_TEST_WRITE_CONCERN": "1"}),
+	})
+	if err != nil {
+		t.Fatalf("newLoadOptions() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "ARANGOM_TEST_SHARDS", want: "9", wantOk: true},
+		{name: "ARANGOM_TEST_REPLICATION_FACTOR", want: "2", wantOk: true},
+		{name: "ARANGOM_TEST_WRITE_CONCERN", want: "1", wantOk: true},
+		{name: "ARANGOM_TEST_UNDEFINED", want: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := options.lookupVariable(tt.name)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("lookupVariable(%s) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package arangom
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OrderByFilename orders the migrations by their file name, regardless of
+	// the directory they are in.
+	OrderByFilename OrderStrategy = iota
+	// OrderByID orders the migrations by their ID.
+	OrderByID
+	// OrderByVersion orders the migrations by the version prefix of their file
+	// name, like 1.2.10 of "v1.2.10_create_users.yaml", comparing the parts of
+	// the version numerically.
+	OrderByVersion
+)
+
+var (
+	// ErrDuplicateMigrationID is returned when two migrations have the same ID.
+	ErrDuplicateMigrationID = fmt.Errorf("duplicate migration ID")
+	// ErrInvalidMigrationVersion is returned when the file name of a migration
+	// has no version prefix.
+	ErrInvalidMigrationVersion = fmt.Errorf("invalid migration version")
+	// ErrInvalidOrderStrategy is returned when an unknown order strategy is
+	// provided.
+	ErrInvalidOrderStrategy = fmt.Errorf("invalid order strategy")
+
+	// migrationExtensions are the extensions of the migration files.
+	migrationExtensions = []string{".yaml", ".yml", ".json", ".aql"}
+)
+
+// OrderStrategy is the strategy of ordering the loaded migrations.
+type OrderStrategy int
+
+// String returns the name of the order strategy.
+func (o OrderStrategy) String() string {
+	switch o {
+	case OrderByFilename:
+		return "filename"
+	case OrderByID:
+		return "id"
+	case OrderByVersion:
+		return "version"
+	default:
+		return fmt.Sprintf("OrderStrategy(%d)", int(o))
+	}
+}
+
+// ParseOrderStrategy returns the order strategy of the given name.
+func ParseOrderStrategy(name string) (OrderStrategy, error) {
+	for _, o := range []OrderStrategy{OrderByFilename, OrderByID, OrderByVersion} {
+		if o.String() == name {
+			return o, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrInvalidOrderStrategy, name)
+}
+
+// LoadOption is a function that sets configuration options of loading the
+// migrations.
+type LoadOption func(*loadOptions) error
+
+// loadOptions is the configuration of loading the migrations.
+type loadOptions struct {
+	order      OrderStrategy
+	logger     Logger
+	variables  map[string]string
+	registered *registry
+}
+
+// WithOrderStrategy sets the strategy of ordering the loaded migrations. The
+// migrations are ordered by file name by default.
+func WithOrderStrategy(order OrderStrategy) LoadOption {
+	return func(o *loadOptions) error {
+		if order < OrderByFilename || order > OrderByVersion {
+			return fmt.Errorf("%w: %s", ErrInvalidOrderStrategy, order)
+		}
+
+		o.order = order
+		return nil
+	}
+}
+
+// WithLoadLogger sets the logger used to warn about files that are not
+// migration files.
+func WithLoadLogger(logger Logger) LoadOption {
+	return func(o *loadOptions) error {
+		if logger == nil {
+			return ErrNoLogger
+		}
+
+		o.logger = logger
+		return nil
+	}
+}
+
+// WithRegisteredMigrations loads the Go migrations registered by Register
+// along with the migration files.
+func WithRegisteredMigrations() LoadOption {
+	return func(o *loadOptions) error {
+		o.registered = registeredMigrations
+		return nil
+	}
+}
+
+// goMigrations returns the Go migrations to load along with the
+// migration files.
+func (o *loadOptions) goMigrations() []*Migration {
+	if o.registered == nil {
+		return nil
+	}
+
+	return o.registered.migrations()
+}
+
+// newLoadOptions returns the configuration of loading the migrations set by
+// the given options.
+func newLoadOptions(opts []LoadOption) (*loadOptions, error) {
+	options := &loadOptions{
+		logger: NewDefaultLogger(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, err
+		}
+	}
+
+	return options, nil
+}
+
+// LoadMigration loads a single migration file from the file system. The path
+// of the migration is the path of the file in the file system. YAML and JSON
+// files share the same schema, which is enforced strictly: unknown fields are
+// reported with their position as SchemaError values. The queries of AQL files
+// become executeAQL operations. The variable references of the file are
+// substituted before it is parsed, but the checksum of the migration is
+// computed from the file as written, so it does not depend on the variables.
+func LoadMigration(fsys fs.FS, name string, opts ...LoadOption) (*Migration, error) {
+	options, err := newLoadOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadMigration(fsys, name, options)
+}
+
+// loadMigration loads a single migration file from the file system with the
+// given configuration.
+func loadMigration(fsys fs.FS, name string, options *loadOptions) (*Migration, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, substitutions, err := substitute(name, b, options.lookupVariable)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parseMigration(name, rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFixedID(name, rendered, substitutions); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(rendered, b) {
+		m.source = b
+	}
+
+	m.Path = name
+
+	if err := m.checkTransaction(); err != nil {
+		return nil, err
+	}
+
+	if err := loadDocumentFiles(fsys, name, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// checkFixedID returns an error wrapping ErrInvalidVariable if the ID of the
+// migration file is substituted, as the ID must identify the migration in
+// every environment.
+func checkFixedID(name string, rendered []byte, substitutions []substitution) error {
+	if len(substitutions) == 0 {
+		return nil
+	}
+
+	start, end := idRange(name, rendered)
+	for _, s := range substitutions {
+		if s.start <= end && s.end >= start && start < end {
+			return fmt.Errorf("%w: %s: the id must not use variables", ErrInvalidVariable, name)
+		}
+	}
+
+	return nil
+}
+
+// idRange returns the byte range of the ID in the migration file, which is
+// the id directive of AQL files and the id value of YAML and JSON files. If
+// the file has no ID, an empty range is returned.
+func idRange(name string, b []byte) (int, int) {
+	if path.Ext(name) == ".aql" {
+		offset := 0
+		for line := range bytes.Lines(b) {
+			if directive, ok := aqlDirective(string(line)); ok && strings.HasPrefix(directive, aqlIDDirective) {
+				return offset, offset + len(line)
+			}
+
+			offset += len(line)
+		}
+
+		return 0, 0
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil || len(node.Content) == 0 {
+		return 0, 0
+	}
+
+	doc := node.Content[0]
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if key, value := doc.Content[i], doc.Content[i+1]; key.Value == "id" {
+			start := nodeOffset(b, value)
+			end := start + len(value.Value)
+			if value.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+				end += 2
+			}
+
+			return start, end
+		}
+	}
+
+	return 0, 0
+}
+
+// nodeOffset returns the byte offset of the node in the file it was parsed
+// from.
+func nodeOffset(b []byte, node *yaml.Node) int {
+	line, column := 1, 1
+	for offset, c := range string(b) {
+		if line == node.Line && column == node.Column {
+			return offset
+		}
+
+		if c == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+
+	return len(b)
+}
+
+// parseMigration parses a migration file by its extension.
+func parseMigration(name string, b []byte) (*Migration, error) {
+	if path.Ext(name) == ".aql" {
+		return parseAQLMigration(name, b)
+	}
+
+	return decodeMigration(name, b)
+}
+
+// LoadMigrations walks the root directory of the file system and loads all the
+// migration files, ordered by the configured order strategy. Migration files
+// have the .yaml, .yml, .json or .aql extension, other files are skipped with a
+// warning. Hidden files and directories, Go files, as well as the document
+// files imported by the migrations, are skipped silently. The Go migrations
+// registered by Register are loaded along with the migration files if the
+// WithRegisteredMigrations option is given. The file system can be any fs.FS,
+// like an embed.FS, os.DirFS or fstest.MapFS. If two migrations have the same
+// ID, an error wrapping ErrDuplicateMigrationID is returned naming both files.
+func LoadMigrations(fsys fs.FS, root string, opts ...LoadOption) ([]*Migration, error) {
+	options, err := newLoadOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := loadMigrationFiles(fsys, root, options)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*Migration, 0, len(files))
+	for _, file := range files {
+		if file.err != nil {
+			return nil, file.err
+		}
+
+		migrations = append(migrations, file.migration)
+	}
+
+	migrations = append(migrations, options.goMigrations()...)
+
+	if err := checkDuplicateMigrations(migrations); err != nil {
+		return nil, err
+	}
+
+	if err := SortMigrations(migrations, options.order); err != nil {
+		return nil, err
+	}
+
+	return migrations, nil
+}
+
+// migrationFile is a migration file with the migration loaded from it, or the
+// error of loading it.
+type migrationFile struct {
+	name      string
+	migration *Migration
+	err       error
+}
+
+// loadMigrationFiles walks the root directory of the file system and loads
+// every migration file in walk order, without stopping at the first error.
+// Files imported by the importDocuments operations of the loaded migrations
+// are not migration files, even if their extension says otherwise. Other files
+// are skipped with a warning logged, while hidden files and directories, as
+// well as Go files, are skipped silently.
+func loadMigrationFiles(fsys fs.FS, root string, options *loadOptions) ([]migrationFile, error) {
+	files := make([]migrationFile, 0)
+	others := make([]string, 0)
+
+	err := fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		hidden := name != root && strings.HasPrefix(d.Name(), ".")
+		if d.IsDir() && hidden {
+			return fs.SkipDir
+		}
+
+		if d.IsDir() || hidden {
+			return nil
+		}
+
+		// The Go migrations are registered, not loaded.
+		if path.Ext(name) == ".go" {
+			return nil
+		}
+
+		if !slices.Contains(migrationExtensions, path.Ext(name)) {
+			others = append(others, name)
+			return nil
+		}
+
+		migration, err := loadMigration(fsys, name, options)
+		files = append(files, migrationFile{name: name, migration: migration, err: err})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]bool)
+	for _, file := range files {
+		if file.migration != nil {
+			maps.Copy(imported, documentFiles(file.migration))
+		}
+	}
+
+	for _, name := range others {
+		if !imported[name] {
+			warnf(options.logger, "skipping %s, it is not a migration file", name)
+		}
+	}
+
+	return slices.DeleteFunc(files, func(file migrationFile) bool {
+		return imported[file.name]
+	}), nil
+}
+
+// SortMigrations sorts the migrations in place by the given order strategy.
+// Migrations in the same position are ordered by their path.
+func SortMigrations(migrations []*Migration, order OrderStrategy) error {
+	var compare func(a, b *Migration) int
+
+	switch order {
+	case OrderByFilename:
+		compare = func(a, b *Migration) int {
+			return strings.Compare(path.Base(a.Path), path.Base(b.Path))
+		}
+	case OrderByID:
+		compare = func(a, b *Migration) int {
+			return cmp.Compare(a.ID, b.ID)
+		}
+	case OrderByVersion:
+		versions := make(map[*Migration][]int, len(migrations))
+		for _, migration := range migrations {
+			version, err := migrationVersion(migration)
+			if err != nil {
+				return err
+			}
+
+			versions[migration] = version
+		}
+
+		compare = func(a, b *Migration) int {
+			return slices.Compare(versions[a], versions[b])
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidOrderStrategy, order)
+	}
+
+	slices.SortStableFunc(migrations, func(a, b *Migration) int {
+		return cmp.Or(compare(a, b), strings.Compare(a.Path, b.Path))
+	})
+
+	return nil
+}
+
+// migrationVersion returns the version prefix of the file name of the
+// migration. The version is an optional "v" followed by numbers separated by
+// dots, and it must be followed by the end of the name, "_" or "-".
+func migrationVersion(migration *Migration) ([]int, error) {
+	name := migration.Name()
+	if strings.HasPrefix(name, "v") || strings.HasPrefix(name, "V") {
+		name = name[1:]
+	}
+
+	prefix, _, _ := strings.Cut(name, "_")
+	prefix, _, _ = strings.Cut(prefix, "-")
+
+	parts := strings.Split(prefix, ".")
+	version := make([]int, 0, len(parts))
+
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s has no version prefix", ErrInvalidMigrationVersion, migration.Path)
+		}
+
+		version = append(version, n)
+	}
+
+	return version, nil
+}
+
+// checkDuplicateMigrations returns an error naming both files if two
+// migrations have the same key.
+func checkDuplicateMigrations(migrations []*Migration) error {
+	if errs := duplicateMigrations(migrations); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// duplicateMigrations returns an error naming both files for every migration
+// having the same key as an earlier one.
+func duplicateMigrations(migrations []*Migration) []error {
+	paths := make(map[string]string, len(migrations))
+	errs := make([]error, 0)
+
+	for _, migration := range migrations {
+		key := migration.Key()
+		if other, ok := paths[key]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s: %s and %s", ErrDuplicateMigrationID, key, other, migration.Path))
+			continue
+		}
+
+		paths[key] = migration.Path
+	}
+
+	return errs
+}
@@ -0,0 +1,393 @@
+package arangom
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/arangodb/go-driver"
+)
+
+func TestLoadMigration(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/123_create_users.yaml": {
+			Data: []byte("id: 123\noperations:\n  - kind: createCollection\n    collection: users\n"),
+		},
+		"migrations/invalid.yaml": {
+			Data: []byte("id: [\n"),
+		},
+		"migrations/125_activate_users.aql": {
+			Data: []byte("// +arangom id: 125\nFOR u IN users UPDATE u WITH { active: true } IN users\n"),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    *Migration
+		wantErr bool
+	}{
+		{
+			name: "load migration",
+			path: "migrations/123_create_users.yaml",
+			want: &Migration{
+				ID:   123,
+				Path: "migrations/123_create_users.yaml",
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+					},
+				},
+			},
+		},
+		{
+			name: "load aql migration",
+			path: "migrations/125_activate_users.aql",
+			want: &Migration{
+				ID:   125,
+				Path: "migrations/125_activate_users.aql",
+				Operations: []*Operation{
+					{
+						Kind:    OperationKindAQLExecute,
+						Options: map[string]any{"query": "FOR u IN users UPDATE u WITH { active: true } IN users"},
+					},
+				},
+			},
+		},
+		{
+			name:    "load invalid migration",
+			path:    "migrations/invalid.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "load missing migration",
+			path:    "migrations/missing.yaml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadMigration(fsys, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadMigration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name      string
+		fsys      fstest.MapFS
+		root      string
+		wantWarns []string
+		wantPaths []string
+		wantErr   bool
+	}{
+		{
+			name: "load migrations in lexical order",
+			fsys: fstest.MapFS{
+				"migrations/124_create_posts.yaml":   {Data: []byte("id: 124\n")},
+				"migrations/123_create_users.yaml":   {Data: []byte("id: 123\n")},
+				"migrations/nested/125_indexes.yaml": {Data: []byte("id: 125\n")},
+			},
+			root: "migrations",
+			wantPaths: []string{
+				"migrations/123_create_users.yaml",
+				"migrations/124_create_posts.yaml",
+				"migrations/nested/125_indexes.yaml",
+			},
+		},
+		{
+			name: "load migrations of every format",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\n")},
+				"migrations/124_create_posts.yml":  {Data: []byte("id: 124\n")},
+				"migrations/125_indexes.json":      {Data: []byte(`{"id": 125, "operations": [{"kind": "createCollection", "collection": "tags"}]}`)},
+			},
+			root: "migrations",
+			wantPaths: []string{
+				"migrations/123_create_users.yaml",
+				"migrations/124_create_posts.yml",
+				"migrations/125_indexes.json",
+			},
+		},
+		{
+			name: "skip other files",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\n")},
+				"migrations/README.md":             {Data: []byte("# Migrations\n")},
+				"migrations/.gitkeep":              {Data: []byte{}},
+				"migrations/124_backfill_users.go": {Data: []byte("package migrations\n")},
+				"migrations/.hidden/124_x.yaml":    {Data: []byte("id: 124\n")},
+			},
+			root:      "migrations",
+			wantWarns: []string{"migrations/README.md"},
+			wantPaths: []string{
+				"migrations/123_create_users.yaml",
+			},
+		},
+		{
+			name: "skip imported document files",
+			fsys: fstest.MapFS{
+				"migrations/123_seed_countries.yaml": {Data: []byte("id: 123\noperations:\n  - kind: importDocuments\n    collection: countries\n    options:\n      file: data/countries.json\n  - kind: importDocuments\n    collection: roles\n    options:\n      file: data/roles.jsonl\n")},
+				"migrations/data/countries.json":     {Data: []byte(`[{"_key": "hu"}]`)},
+				"migrations/data/roles.jsonl":        {Data: []byte(`{"_key": "admin"}`)},
+			},
+			root: "migrations",
+			wantPaths: []string{
+				"migrations/123_seed_countries.yaml",
+			},
+		},
+		{
+			name: "load migrations from the root",
+			fsys: fstest.MapFS{
+				"123_create_users.yaml": {Data: []byte("id: 123\n")},
+			},
+			root:      ".",
+			wantPaths: []string{"123_create_users.yaml"},
+		},
+		{
+			name: "load invalid migration",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: [\n")},
+			},
+			root:    "migrations",
+			wantErr: true,
+		},
+		{
+			name:    "load missing directory",
+			fsys:    fstest.MapFS{},
+			root:    "migrations",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := new(MockLogger)
+			for _, warn := range tt.wantWarns {
+				logger.On("Warnf", "skipping %s, it is not a migration file", []any{warn}).Return().Once()
+			}
+
+			got, err := LoadMigrations(tt.fsys, tt.root, WithLoadLogger(logger))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			logger.AssertExpectations(t)
+
+			var paths []string
+			for _, migration := range got {
+				paths = append(paths, migration.Path)
+			}
+
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("LoadMigrations() = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations_orderStrategy(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/a/v1.10_add_index.yaml":   {Data: []byte("id: 3\n")},
+		"migrations/b/v1.2_create_posts.yaml": {Data: []byte("id: 1\n")},
+		"migrations/v1.9_create_users.yaml":   {Data: []byte("id: 2\n")},
+	}
+
+	tests := []struct {
+		name      string
+		order     OrderStrategy
+		wantPaths []string
+		wantErr   bool
+	}{
+		{
+			name:  "order by filename",
+			order: OrderByFilename,
+			wantPaths: []string{
+				"migrations/a/v1.10_add_index.yaml",
+				"migrations/b/v1.2_create_posts.yaml",
+				"migrations/v1.9_create_users.yaml",
+			},
+		},
+		{
+			name:  "order by ID",
+			order: OrderByID,
+			wantPaths: []string{
+				"migrations/b/v1.2_create_posts.yaml",
+				"migrations/v1.9_create_users.yaml",
+				"migrations/a/v1.10_add_index.yaml",
+			},
+		},
+		{
+			name:  "order by version",
+			order: OrderByVersion,
+			wantPaths: []string{
+				"migrations/b/v1.2_create_posts.yaml",
+				"migrations/v1.9_create_users.yaml",
+				"migrations/a/v1.10_add_index.yaml",
+			},
+		},
+		{
+			name:    "order by unknown strategy",
+			order:   OrderStrategy(-1),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadMigrations(fsys, "migrations", WithOrderStrategy(tt.order))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var paths []string
+			for _, migration := range got {
+				paths = append(paths, migration.Path)
+			}
+
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("LoadMigrations() = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations_duplicateID(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"migrations/123_create_users.yaml": {Data: []byte("id: 123\n")},
+		"migrations/123_create_posts.yaml": {Data: []byte("id: 123\n")},
+	}
+
+	_, err := LoadMigrations(fsys, "migrations")
+	if !errors.Is(err, ErrDuplicateMigrationID) {
+		t.Fatalf("LoadMigrations() error = %v, wantErr %v", err, ErrDuplicateMigrationID)
+	}
+
+	want := "duplicate migration ID: 123: migrations/123_create_posts.yaml and migrations/123_create_users.yaml"
+	if err.Error() != want {
+		t.Errorf("LoadMigrations() error = %v, want %v", err, want)
+	}
+}
+
+func TestLoadMigrations_registeredMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/123_create_users.yaml": {Data: []byte("id: 123\n")},
+	}
+
+	r := new(registry)
+	up := func(context.Context, driver.Database) error { return nil }
+	if err := r.register(&goMigration{id: 124, name: "124_backfill_users", up: up}); err != nil {
+		t.Fatalf("registry.register() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      []LoadOption
+		wantPaths []string
+	}{
+		{
+			name:      "load migration files only",
+			wantPaths: []string{"migrations/123_create_users.yaml"},
+		},
+		{
+			name: "load registered migrations",
+			opts: []LoadOption{func(o *loadOptions) error {
+				o.registered = r
+				return nil
+			}},
+			wantPaths: []string{"migrations/123_create_users.yaml", "124_backfill_users"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadMigrations(fsys, "migrations", tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadMigrations() error = %v", err)
+			}
+
+			paths := make([]string, 0, len(got))
+			for _, m := range got {
+				paths = append(paths, m.Path)
+			}
+
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("LoadMigrations() = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestSortMigrations_invalidVersion(t *testing.T) {
+	t.Parallel()
+
+	migrations := []*Migration{
+		{ID: 1, Path: "v1_create_users.yaml"},
+		{ID: 2, Path: "create_posts.yaml"},
+	}
+
+	if err := SortMigrations(migrations, OrderByVersion); !errors.Is(err, ErrInvalidMigrationVersion) {
+		t.Errorf("SortMigrations() error = %v, wantErr %v", err, ErrInvalidMigrationVersion)
+	}
+}
+
+func TestParseOrderStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    OrderStrategy
+		wantErr bool
+	}{
+		{
+			name: "filename",
+			want: OrderByFilename,
+		},
+		{
+			name: "id",
+			want: OrderByID,
+		},
+		{
+			name: "version",
+			want: OrderByVersion,
+		},
+		{
+			name:    "unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseOrderStrategy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOrderStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseOrderStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package arangom
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/arangodb/go-driver"
+)
+
+const (
+	// LockKey is the document key of the lock in the migration collection.
+	LockKey = "arangom-lock"
+	// DefaultLockTTL is the default time after the lock is considered stale
+	// if its owner stops sending heartbeats.
+	DefaultLockTTL = 30 * time.Second
+	// DefaultLockTimeout is the default time to wait for the lock.
+	DefaultLockTimeout = time.Minute
+)
+
+var (
+	// ErrLockTimeout is returned when the lock cannot be acquired in time.
+	ErrLockTimeout = fmt.Errorf("timed out waiting for the migration lock")
+	// ErrInvalidLockTTL is returned when an invalid lock TTL is provided.
+	ErrInvalidLockTTL = fmt.Errorf("invalid lock TTL")
+	// ErrInvalidLockTimeout is returned when an invalid lock timeout is provided.
+	ErrInvalidLockTimeout = fmt.Errorf("invalid lock timeout")
+	// ErrLockLost is returned when the migration lock is lost while it is held,
+	// because it was removed, taken over or could not be extended in time.
+	ErrLockLost = fmt.Errorf("migration lock lost")
+
+	// lockRetryInterval is the time to wait between two attempts to acquire
+	// the lock.
+	lockRetryInterval = time.Second
+)
+
+// LockItem represents the lock in the migration collection.
+type LockItem struct {
+	Key         string    `json:"_key"`
+	Owner       string    `json:"owner"`
+	AcquiredAt  time.Time `json:"acquiredAt"`
+	HeartbeatAt time.Time `json:"heartbeatAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// migrationLock is a lock held by the executor. If the lock is lost, lost is
+// set and onLost is called by the heartbeats before done is closed.
+type migrationLock struct {
+	mu     sync.Mutex
+	owner  string
+	rev    string
+	lost   error
+	onLost func(err error)
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// revision returns the current revision of the lock document.
+func (l *migrationLock) revision() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rev
+}
+
+// setRevision sets the current revision of the lock document.
+func (l *migrationLock) setRevision(rev string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rev = rev
+}
+
+// lose records that the lock is lost and notifies the holder of the lock.
+func (l *migrationLock) lose(err error) {
+	l.lost = err
+	if l.onLost != nil {
+		l.onLost(err)
+	}
+}
+
+// newLockOwner returns a unique identifier of the lock owner.
+func newLockOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+
+	return fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), hex.EncodeToString(b))
+}
+
+// withLock acquires the lock, calls fn and releases the lock. The context
+// passed to fn is canceled if the lock is lost, in which case ErrLockLost is
+// returned, so fn never runs without holding the lock.
+func (e *Executor) withLock(ctx context.Context, coll driver.Collection, fn func(ctx context.Context) error) error {
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	lock, err := e.acquireLock(ctx, coll, cancel)
+	if err != nil {
+		return err
+	}
+
+	err = fn(lockCtx)
+	e.releaseLock(context.WithoutCancel(ctx), coll, lock)
+
+	if lost := context.Cause(lockCtx); errors.Is(lost, ErrLockLost) {
+		e.logger.Errorf("migration lock lost while running; err=%s", lost.Error())
+		if err != nil {
+			return errors.Join(lost, err)
+		}
+
+		return lost
+	}
+
+	return err
+}
+
+// acquireLock acquires the lock, waiting for it at most the lock timeout. Once
+// acquired, the lock is kept alive by heartbeats until it is released. If the
+// lock is lost, onLost is called with an error wrapping ErrLockLost.
+func (e *Executor) acquireLock(ctx context.Context, coll driver.Collection, onLost func(err error)) (*migrationLock, error) {
+	lock := &migrationLock{
+		owner:  newLockOwner(),
+		onLost: onLost,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	deadline := time.Now().Add(e.lockTimeout)
+	for {
+		holder, err := e.tryAcquireLock(ctx, coll, lock)
+		if err != nil {
+			return nil, err
+		}
+
+		if holder == "" {
+			break
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			e.logger.Errorf("migration lock is held by %s", holder)
+			return nil, fmt.Errorf("%w: held by %s", ErrLockTimeout, holder)
+		}
+
+		e.logger.Infof("waiting for the migration lock held by %s", holder)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(min(lockRetryInterval, remaining)):
+		}
+	}
+
+	go e.heartbeatLock(context.WithoutCancel(ctx), coll, lock)
+
+	return lock, nil
+}
+
+// tryAcquireLock tries to acquire the lock once. If the lock is held by an
+// other owner, the owner is returned. Stale locks are taken over.
+func (e *Executor) tryAcquireLock(ctx context.Context, coll driver.Collection, lock *migrationLock) (string, error) {
+	now := time.Now().UTC()
+	item := &LockItem{
+		Key:         LockKey,
+		Owner:       lock.owner,
+		AcquiredAt:  now,
+		HeartbeatAt: now,
+		ExpiresAt:   now.Add(e.lockTTL),
+	}
+
+	meta, err := coll.CreateDocument(ctx, item)
+	if err == nil {
+		lock.setRevision(meta.Rev)
+		return "", nil
+	}
+
+	if !driver.IsConflict(err) {
+		return "", err
+	}
+
+	current := new(LockItem)
+	meta, err = coll.ReadDocument(ctx, LockKey, current)
+	if err != nil {
+		// The lock was released in the meantime.
+		if driver.IsNotFound(err) {
+			return e.tryAcquireLock(ctx, coll, lock)
+		}
+
+		return "", err
+	}
+
+	if current.ExpiresAt.After(now) {
+		return current.Owner, nil
+	}
+
+	e.logger.Infof("taking over the stale migration lock of %s", current.Owner)
+	meta, err = coll.ReplaceDocument(driver.WithRevision(ctx, meta.Rev), LockKey, item)
+	if err != nil {
+		// Someone else took over the lock first.
+		if driver.IsPreconditionFailed(err) || driver.IsNotFound(err) {
+			return current.Owner, nil
+		}
+
+		return "", err
+	}
+
+	lock.setRevision(meta.Rev)
+
+	return "", nil
+}
+
+// heartbeatLock extends the expiry of the lock periodically until the lock is
+// released. The lock is lost if its document was removed or replaced by an
+// other owner, or if it could not be extended for a full TTL.
+func (e *Executor) heartbeatLock(ctx context.Context, coll driver.Collection, lock *migrationLock) {
+	defer close(lock.done)
+
+	ticker := time.NewTicker(e.lockTTL / 3)
+	defer ticker.Stop()
+
+	extendedAt := time.Now()
+	for {
+		select {
+		case <-lock.stop:
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			update := map[string]any{
+				"heartbeatAt": now,
+				"expiresAt":   now.Add(e.lockTTL),
+			}
+
+			meta, err := coll.UpdateDocument(driver.WithRevision(ctx, lock.revision()), LockKey, update)
+			if err != nil {
+				if driver.IsPreconditionFailed(err) || driver.IsNotFound(err) {
+					lock.lose(fmt.Errorf("%w: %w", ErrLockLost, err))
+					return
+				}
+
+				e.logger.Errorf("failed to extend the migration lock; err=%s", err.Error())
+				if time.Since(extendedAt) >= e.lockTTL {
+					lock.lose(fmt.Errorf("%w: not extended for %s: %w", ErrLockLost, e.lockTTL, err))
+					return
+				}
+
+				continue
+			}
+
+			extendedAt = time.Now()
+			lock.setRevision(meta.Rev)
+		}
+	}
+}
+
+// releaseLock stops the heartbeats and removes the lock if it is still held.
+func (e *Executor) releaseLock(ctx context.Context, coll driver.Collection, lock *migrationLock) {
+	close(lock.stop)
+	<-lock.done
+
+	// The lost lock may be held by an other owner already.
+	if lock.lost != nil {
+		return
+	}
+
+	if _, err := coll.RemoveDocument(driver.WithRevision(ctx, lock.revision()), LockKey); err != nil {
+		e.logger.Errorf("failed to release the migration lock; err=%s", err.Error())
+	}
+}
+
+// ForceUnlock removes the lock from the migration collection regardless of its
+// owner. It is meant to be used to remove stale locks left by crashed runs.
+func (e *Executor) ForceUnlock(ctx context.Context) error {
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	coll, err := e.db.Collection(ctx, e.collection)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.RemoveDocument(ctx, LockKey); err != nil {
+		if driver.IsNotFound(err) {
+			e.logger.Info("migration lock is not held")
+			return nil
+		}
+
+		return err
+	}
+
+	e.logger.Info("migration lock removed")
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package arangom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockLock registers the calls acquiring and releasing a free migration lock.
+func mockLock(coll *MockArangoCollection) {
+	coll.On("CreateDocument", mock.Anything, mock.AnythingOfType("*arangom.LockItem")).Return(driver.DocumentMeta{Rev: "1"}, nil)
+	coll.On("RemoveDocument", mock.Anything, LockKey).Return(driver.DocumentMeta{}, nil)
+}
+
+func TestNewLockOwner(t *testing.T) {
+	t.Parallel()
+
+	owner := newLockOwner()
+	if strings.Count(owner, "/") != 2 {
+		t.Errorf("newLockOwner() = %v, want hostname/pid/random", owner)
+	}
+
+	if other := newLockOwner(); other == owner {
+		t.Errorf("newLockOwner() = %v, want unique owners", other)
+	}
+}
+
+func TestExecutor_acquireLock(t *testing.T) {
+	conflict := driver.ArangoError{HasError: true, Code: http.StatusConflict}
+	preconditionFailed := driver.ArangoError{HasError: true, Code: http.StatusPreconditionFailed}
+
+	tests := []struct {
+		name        string
+		coll        func() *MockArangoCollection
+		logger      func() *MockLogger
+		lockTimeout time.Duration
+		wantErr     error
+	}{
+		{
+			name: "acquire free lock",
+			coll: func() *MockArangoCollection {
+				coll := new(MockArangoCollection)
+				mockLock(coll)
+				return coll
+			},
+			logger: func() *MockLogger {
+				return new(MockLogger)
+			},
+		},
+		{
+			name: "acquire held lock",
+			coll: func() *MockArangoCollection {
+				coll := new(MockArangoCollection)
+				coll.On("CreateDocument", mock.Anything, mock.Anything).Return(driver.DocumentMeta{}, conflict)
+				coll.On("ReadDocument", mock.Anything, LockKey, mock.Anything).Return(&LockItem{
+					Owner:     "other",
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, driver.DocumentMeta{Rev: "1"}, nil)
+				return coll
+			},
+			logger: func() *MockLogger {
+				logger := new(MockLogger)
+				logger.On("Errorf", "migration lock is held by %s", []any{"other"}).Return()
+				return logger
+			},
+			wantErr: ErrLockTimeout,
+		},
+		{
+			name: "acquire stale lock",
+			coll: func() *MockArangoCollection {
+				coll := new(MockArangoCollection)
+				coll.On("CreateDocument", mock.Anything, mock.Anything).Return(driver.DocumentMeta{}, conflict)
+				coll.On("ReadDocument", mock.Anything, LockKey, mock.Anything).Return(&LockItem{
+					Owner:     "other",
+					ExpiresAt: time.Now().Add(-time.Hour),
+				}, driver.DocumentMeta{Rev: "1"}, nil)
+				coll.On("ReplaceDocument", mock.Anything, LockKey, mock.Anything).Return(driver.DocumentMeta{Rev: "2"}, nil)
+				coll.On("RemoveDocument", mock.Anything, LockKey).Return(driver.DocumentMeta{}, nil)
+				return coll
+			},
+			logger: func() *MockLogger {
+				logger := new(MockLogger)
+				logger.On("Infof", "taking over the stale migration lock of %s", []any{"other"}).Return()
+				return logger
+			},
+		},
+		{
+			name: "acquire stale lock taken over by other owner",
+			coll: func() *MockArangoCollection {
+				coll := new(MockArangoCollection)
+				coll.On("CreateDocument", mock.Anything, mock.Anything).Return(driver.DocumentMeta{}, conflict)
+				coll.On("ReadDocument", mock.Anything, LockKey, mock.Anything).Return(&LockItem{
+					Owner:     "other",
+					ExpiresAt: time.Now().Add(-time.Hour),
+				}, driver.DocumentMeta{Rev: "1"}, nil)
+				coll.On("ReplaceDocument", mock.Anything, LockKey, mock.Anything).Return(driver.DocumentMeta{}, preconditionFailed)
+				return coll
+			},
+			logger: func() *MockLogger {
+				logger := new(MockLogger)
+				logger.On("Infof", "taking over the stale migration lock of %s", []any{"other"}).Return()
+				logger.On("Errorf", "migration lock is held by %s", []any{"other"}).Return()
+				return logger
+			},
+			wantErr: ErrLockTimeout,
+		},
+		{
+			name: "acquire lock with error",
+			coll: func() *MockArangoCollection {
+				coll := new(MockArangoCollection)
+				coll.On("CreateDocument", mock.Anything, mock.Anything).Return(driver.DocumentMeta{}, fmt.Errorf("error"))
+				return coll
+			},
+			logger: func() *MockLogger {
+				return new(MockLogger)
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			coll := tt.coll()
+			e := &Executor{
+				logger:      tt.logger(),
+				lockTimeout: tt.lockTimeout,
+				lockTTL:     DefaultLockTTL,
+			}
+
+			lock, err := e.acquireLock(context.Background(), coll, nil)
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("Executor.acquireLock() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Executor.acquireLock() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			e.releaseLock(context.Background(), coll, lock)
+			coll.AssertCalled(t, "RemoveDocument", mock.Anything, LockKey)
+		})
+	}
+}
+
+func TestExecutor_heartbeatLock(t *testing.T) {
+	t.Parallel()
+
+	coll := new(MockArangoCollection)
+	mockLock(coll)
+	coll.On("UpdateDocument", mock.Anything, LockKey, mock.Anything).Return(driver.DocumentMeta{Rev: "2"}, nil)
+
+	e := &Executor{
+		logger:  new(MockLogger),
+		lockTTL: 30 * time.Millisecond,
+	}
+
+	lock, err := e.acquireLock(context.Background(), coll, nil)
+	if err != nil {
+		t.Fatalf("Executor.acquireLock() error = %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	e.releaseLock(context.Background(), coll, lock)
+
+	coll.AssertCalled(t, "UpdateDocument", mock.Anything, LockKey, mock.Anything)
+	if got := lock.revision(); got != "2" {
+		t.Errorf("migrationLock.revision() = %v, want %v", got, "2")
+	}
+}
+
+func TestExecutor_withLock_lost(t *testing.T) {
+	tests := []struct {
+		name      string
+		updateErr error
+	}{
+		{
+			name:      "lock removed by an other owner",
+			updateErr: driver.ArangoError{HasError: true, Code: http.StatusNotFound},
+		},
+		{
+			name:      "lock taken over by an other owner",
+			updateErr: driver.ArangoError{HasError: true, Code: http.StatusPreconditionFailed},
+		},
+		{
+			name:      "lock not extended for a full TTL",
+			updateErr: fmt.Errorf("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			coll := new(MockArangoCollection)
+			coll.On("CreateDocument", mock.Anything, mock.AnythingOfType("*arangom.LockItem")).Return(driver.DocumentMeta{Rev: "1"}, nil)
+			coll.On("UpdateDocument", mock.Anything, LockKey, mock.Anything).Return(driver.DocumentMeta{}, tt.updateErr)
+
+			logger := new(MockLogger)
+			logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+			e := &Executor{
+				logger:  logger,
+				lockTTL: 30 * time.Millisecond,
+			}
+
+			err := e.withLock(context.Background(), coll, func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Second):
+					return nil
+				}
+			})
+			if !errors.Is(err, ErrLockLost) {
+				t.Fatalf("Executor.withLock() error = %v, want %v", err, ErrLockLost)
+			}
+
+			coll.AssertNotCalled(t, "RemoveDocument", mock.Anything, LockKey)
+		})
+	}
+}
+
+func TestExecutor_ForceUnlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      func() driver.Database
+		logger  func() Logger
+		wantErr bool
+	}{
+		{
+			name: "force unlock held lock",
+			db: func() driver.Database {
+				coll := new(MockArangoCollection)
+				coll.On("RemoveDocument", context.Background(), LockKey).Return(driver.DocumentMeta{}, nil)
+
+				db := new(MockArangoDB)
+				db.On("Collection", context.Background(), "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Info", []any{"migration lock removed"}).Return()
+				return logger
+			},
+		},
+		{
+			name: "force unlock free lock",
+			db: func() driver.Database {
+				coll := new(MockArangoCollection)
+				coll.On("RemoveDocument", context.Background(), LockKey).Return(driver.DocumentMeta{}, driver.ArangoError{HasError: true, Code: http.StatusNotFound})
+
+				db := new(MockArangoDB)
+				db.On("Collection", context.Background(), "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				logger.On("Info", []any{"migration lock is not held"}).Return()
+				return logger
+			},
+		},
+		{
+			name: "force unlock with error",
+			db: func() driver.Database {
+				coll := new(MockArangoCollection)
+				coll.On("RemoveDocument", context.Background(), LockKey).Return(driver.DocumentMeta{}, fmt.Errorf("error"))
+
+				db := new(MockArangoDB)
+				db.On("Collection", context.Background(), "test").Return(coll, nil)
+				return db
+			},
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+				return logger
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.db(),
+				collection: "test",
+				logger:     tt.logger(),
+			}
+
+			if err := e.ForceUnlock(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Executor.ForceUnlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -26,6 +26,24 @@ type Logger interface {
 	Fatalf(format string, args ...any)
 }
 
+// WarnLogger is implemented by loggers that log warnings. Warnings are
+// logged as informational messages by loggers not implementing it.
+type WarnLogger interface {
+	// Warnf logs a formatted warning message.
+	Warnf(format string, args ...any)
+}
+
+// warnf logs a formatted warning message with the logger, falling back to an
+// informational message if the logger does not log warnings.
+func warnf(logger Logger, format string, args ...any) {
+	if l, ok := logger.(WarnLogger); ok {
+		l.Warnf(format, args...)
+		return
+	}
+
+	logger.Infof("warning: "+format, args...)
+}
+
 // LogWriter is an interface that is used to write log messages.
 type LogWriter interface {
 	WriteString(s string) (n int, err error)
@@ -53,6 +71,14 @@ func (l *DefaultLogger) Infof(format string, args ...any) {
 	l.log("[%s] %s", "INFO", fmt.Sprintf(format, args...))
 }
 
+func (l *DefaultLogger) Warn(args ...any) {
+	l.Warnf("%s", fmt.Sprint(args...))
+}
+
+func (l *DefaultLogger) Warnf(format string, args ...any) {
+	l.log("[%s] %s", "WARN", fmt.Sprintf(format, args...))
+}
+
 func (l *DefaultLogger) Error(args ...any) {
 	l.Errorf("%s", fmt.Sprint(args...))
 }
@@ -19,6 +19,14 @@ func (m *MockLogger) Infof(format string, args ...any) {
 	m.Called(format, args)
 }
 
+func (m *MockLogger) Warn(args ...any) {
+	m.Called(args)
+}
+
+func (m *MockLogger) Warnf(format string, args ...any) {
+	m.Called(format, args)
+}
+
 func (m *MockLogger) Error(args ...any) {
 	m.Called(args)
 }
@@ -89,6 +97,73 @@ func TestDefaultLogger_Infof(t *testing.T) {
 	l.Infof("formatted: %s", "test")
 }
 
+func TestDefaultLogger_Warn(t *testing.T) {
+	t.Parallel()
+
+	w := new(MockLogWriter)
+	w.On("WriteString", "[WARN] test\n").Return(0, nil)
+
+	l := &DefaultLogger{
+		Writer: w,
+	}
+
+	l.Warn("test")
+}
+
+func TestDefaultLogger_Warnf(t *testing.T) {
+	t.Parallel()
+
+	w := new(MockLogWriter)
+	w.On("WriteString", "[WARN] formatted: test\n").Return(0, nil)
+
+	l := &DefaultLogger{
+		Writer: w,
+	}
+
+	l.Warnf("formatted: %s", "test")
+}
+
+func TestWarnf(t *testing.T) {
+	tests := []struct {
+		name   string
+		logger func() (Logger, *MockLogger)
+		method string
+		format string
+	}{
+		{
+			name: "warn with warn logger",
+			logger: func() (Logger, *MockLogger) {
+				logger := new(MockLogger)
+				logger.On("Warnf", "skipping %s", []any{"README.md"}).Return()
+				return logger, logger
+			},
+			method: "Warnf",
+			format: "skipping %s",
+		},
+		{
+			name: "warn with logger not logging warnings",
+			logger: func() (Logger, *MockLogger) {
+				logger := new(MockLogger)
+				logger.On("Infof", "warning: skipping %s", []any{"README.md"}).Return()
+				return struct{ Logger }{logger}, logger
+			},
+			method: "Infof",
+			format: "warning: skipping %s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, mockLogger := tt.logger()
+			warnf(logger, "skipping %s", "README.md")
+
+			mockLogger.AssertCalled(t, tt.method, tt.format, []any{"README.md"})
+		})
+	}
+}
+
 func TestDefaultLogger_Error(t *testing.T) {
 	t.Parallel()
 
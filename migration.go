@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +22,14 @@ const (
 	MigrationStatusDone
 	// MigrationStatusFailed is the status of a migration that has failed.
 	MigrationStatusFailed
+	// MigrationStatusRolledBack is the status of a migration that has been rolled back.
+	MigrationStatusRolledBack
+	// MigrationStatusRollingBack is the status of a migration that is currently
+	// rolled back.
+	MigrationStatusRollingBack
+	// MigrationStatusRollbackFailed is the status of a migration that has failed
+	// to roll back.
+	MigrationStatusRollbackFailed
 )
 
 const (
@@ -34,8 +44,55 @@ var (
 	ErrNoMigrations = fmt.Errorf("no migrations provided")
 	// ErrMigrationFailed is returned when a migration has failed.
 	ErrMigrationFailed = fmt.Errorf("migration failed")
+	// ErrRollbackFailed is returned when rolling back a migration has failed.
+	ErrRollbackFailed = fmt.Errorf("rollback failed")
+	// ErrIrreversibleMigration is returned when a migration has no down
+	// operations and they cannot be derived from its operations.
+	ErrIrreversibleMigration = fmt.Errorf("irreversible migration")
+	// ErrMigrationNotFound is returned when a migration cannot be found.
+	ErrMigrationNotFound = fmt.Errorf("migration not found")
+	// ErrInvalidRollbackSteps is returned when the number of rollback steps is
+	// less than one.
+	ErrInvalidRollbackSteps = fmt.Errorf("invalid rollback steps")
+	// ErrMigrationNotRecorded is returned when a migration was executed
+	// successfully, but its status could not be recorded.
+	ErrMigrationNotRecorded = fmt.Errorf("migration executed but its status is not recorded")
+	// ErrChecksumMismatch is returned when an applied migration has changed
+	// since it was executed.
+	ErrChecksumMismatch = fmt.Errorf("checksum mismatch")
+	// ErrOutOfOrderMigration is returned when a pending migration precedes an
+	// applied migration.
+	ErrOutOfOrderMigration = fmt.Errorf("out of order migration")
 )
 
+const (
+	// MigrationPhaseFetchStatus is the phase of fetching the migration status.
+	MigrationPhaseFetchStatus MigrationPhase = "fetch status"
+	// MigrationPhaseSaveStatus is the phase of saving the migration status.
+	MigrationPhaseSaveStatus MigrationPhase = "save status"
+)
+
+// MigrationPhase is the phase of processing a migration.
+type MigrationPhase string
+
+// MigrationError is returned when processing a migration fails in one of its
+// phases, apart from running its operations.
+type MigrationError struct {
+	ID    int
+	Phase MigrationPhase
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("migration %d: %s: %s", e.ID, e.Phase, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
 // MigrationStatus is the status of a migration.
 type MigrationStatus int
 
@@ -54,6 +111,15 @@ func (s *MigrationStatus) UnmarshalJSON(status []byte) error {
 	case `"failed"`:
 		*s = MigrationStatusFailed
 		return nil
+	case `"rolledBack"`:
+		*s = MigrationStatusRolledBack
+		return nil
+	case `"rollingBack"`:
+		*s = MigrationStatusRollingBack
+		return nil
+	case `"rollbackFailed"`:
+		*s = MigrationStatusRollbackFailed
+		return nil
 	default:
 		return fmt.Errorf("%w: %s", ErrInvalidMigrationStatus, status)
 	}
@@ -70,17 +136,91 @@ func (s *MigrationStatus) MarshalJSON() ([]byte, error) {
 		return []byte(`"done"`), nil
 	case MigrationStatusFailed:
 		return []byte(`"failed"`), nil
+	case MigrationStatusRolledBack:
+		return []byte(`"rolledBack"`), nil
+	case MigrationStatusRollingBack:
+		return []byte(`"rollingBack"`), nil
+	case MigrationStatusRollbackFailed:
+		return []byte(`"rollbackFailed"`), nil
 	default:
 		return nil, fmt.Errorf("%w: %d", ErrInvalidMigrationStatus, s)
 	}
 }
 
-// MigrationItem represents a migration in the collection.
+// String returns the name of the migration status.
+func (s MigrationStatus) String() string {
+	switch s {
+	case MigrationStatusMissing:
+		return "missing"
+	case MigrationStatusRunning:
+		return "running"
+	case MigrationStatusDone:
+		return "done"
+	case MigrationStatusFailed:
+		return "failed"
+	case MigrationStatusRolledBack:
+		return "rolledBack"
+	case MigrationStatusRollingBack:
+		return "rollingBack"
+	case MigrationStatusRollbackFailed:
+		return "rollbackFailed"
+	default:
+		return fmt.Sprintf("MigrationStatus(%d)", int(s))
+	}
+}
+
+// IsPending returns true if the migration was not run yet or it was rolled
+// back since its last execution.
+func (s MigrationStatus) IsPending() bool {
+	return s == MigrationStatusMissing || s == MigrationStatusRolledBack
+}
+
+// isUnfinished returns true if the execution of the migration failed or it was
+// interrupted. As executions hold the migration lock, a running migration
+// belongs to an execution that crashed.
+func (s MigrationStatus) isUnfinished() bool {
+	return s == MigrationStatusRunning || s == MigrationStatusFailed
+}
+
+// isRollingBack returns true if rolling back the migration failed or it was
+// interrupted.
+func (s MigrationStatus) isRollingBack() bool {
+	return s == MigrationStatusRollingBack || s == MigrationStatusRollbackFailed
+}
+
+// MigrationItem represents a migration in the collection. The key of the item
+// is the key of the migration. Items created by earlier versions are keyed by
+// the checksum of the migration and have no checksum field.
 type MigrationItem struct {
-	Key       string          `json:"_key"`
-	Name      string          `json:"name"`
-	Status    MigrationStatus `json:"status"`
-	AppliedAt time.Time       `json:"appliedAt"`
+	Key         string          `json:"_key"`
+	MigrationID int             `json:"migrationId"`
+	Name        string          `json:"name"`
+	Checksum    string          `json:"checksum"`
+	Status      MigrationStatus `json:"status"`
+	// AppliedAt is the time the migration was last executed successfully.
+	AppliedAt time.Time `json:"appliedAt"`
+	// StartedAt and FinishedAt are the start and end of the latest execution
+	// or rollback of the migration, which took Duration. FinishedAt and
+	// Duration are zero while the migration is running.
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt time.Time     `json:"finishedAt"`
+	Duration   time.Duration `json:"duration"`
+	// Error is the error message of the latest execution or rollback, if it
+	// failed.
+	Error string `json:"error"`
+	// Version is the version of arangom, Hostname and User are the host and
+	// user that last changed the status of the migration.
+	Version  string `json:"version"`
+	Hostname string `json:"hostname"`
+	User     string `json:"user"`
+	// OperationCount is the number of operations of the migration.
+	OperationCount int `json:"operationCount"`
+	// CompletedOperations is the index of every operation of the migration
+	// executed successfully during its latest execution.
+	CompletedOperations []int `json:"completedOperations"`
+	// Checkpoints is the progress of the unfinished resumable operations of
+	// the latest execution, by their index.
+	Checkpoints map[int]string `json:"checkpoints"`
 }
 
 // Migration is a migration that can be run on a database.
@@ -89,110 +229,461 @@ type Migration struct {
 	Path       string          `yaml:"-"`
 	Status     MigrationStatus `yaml:"-"`
 	Operations []*Operation    `yaml:"operations"`
+	Down       []*Operation    `yaml:"down"`
+	// Transaction declares the stream transaction the operations and the down
+	// operations run in, or nil if they run without transaction.
+	Transaction *MigrationTransaction `yaml:"transaction"`
+
+	// record is the item of the migration in the migration collection, or nil
+	// if the migration has no item yet.
+	record *MigrationItem
+	// completed is the index of every operation executed successfully during
+	// the latest execution of the migration.
+	completed []int
+	// checkpoints is the progress of the unfinished resumable operations of
+	// the latest execution of the migration, by their index.
+	checkpoints map[int]string
+	// source is the migration file as written, before the variables were
+	// substituted, or nil if the file has no variables.
+	source []byte
+}
+
+// Key returns the key of the migration in the migration collection. The key is
+// the ID of the migration, or its name if the migration has no ID.
+func (m *Migration) Key() string {
+	if m.ID == 0 {
+		return m.Name()
+	}
+
+	return strconv.Itoa(m.ID)
+}
+
+// StoredChecksum returns the checksum recorded when the migration was last
+// executed. If the migration has no item in the migration collection, or the
+// item was created by an earlier version, an empty string is returned.
+func (m *Migration) StoredChecksum() string {
+	if m.record == nil {
+		return ""
+	}
+
+	return m.record.Checksum
 }
 
 // Name returns the name of the migration. The name is the name of the file
 // without the extension.
 func (m *Migration) Name() string {
 	parts := strings.Split(m.Path, "/")
-	return strings.TrimSuffix(parts[len(parts)-1], ".yaml")
+	name := parts[len(parts)-1]
+
+	for _, ext := range migrationExtensions {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+
+	return name
 }
 
 // Checksum returns the checksum of the migration. The checksum is the SHA256
 // hash of the ID and operations of the migration file. It is used to determine
-// if a migration has been run already.
+// if a migration has changed since it was executed. The down operations are not
+// part of the checksum, therefore they can be added to already applied
+// migrations. For files with variables, the whole file as written, before the
+// variables are substituted, is hashed instead of the operations, so the
+// checksum is the same in every environment. The content of the document files
+// imported by the operations is part of the checksum too.
 func (m *Migration) Checksum() (string, error) {
-	fields := struct {
+	b, err := m.checksumSource()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := h.Write(b); err != nil {
+		return "", err
+	}
+
+	for _, operation := range m.Operations {
+		if _, err := h.Write(operation.content); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// checksumSource returns the content hashed by Checksum: the ID and the
+// operations of the migration, or the ID and the file as written if the file
+// has variables, as it may not be valid before substituting them.
+func (m *Migration) checksumSource() ([]byte, error) {
+	if m.source != nil {
+		return yaml.Marshal(struct {
+			ID   int
+			File string
+		}{
+			ID:   m.ID,
+			File: string(m.source),
+		})
+	}
+
+	return yaml.Marshal(struct {
 		ID         int
 		Operations []*Operation `yaml:"operations"`
 	}{
 		ID:         m.ID,
 		Operations: m.Operations,
+	})
+}
+
+// VerifyChecksum compares the recorded checksum of the applied migration to its
+// current checksum. If the migration changed since it was executed, an error
+// wrapping ErrChecksumMismatch is returned. Pending migrations and migrations
+// recorded without checksum are not verified.
+func (m *Migration) VerifyChecksum() error {
+	stored := m.StoredChecksum()
+	if stored == "" || m.Status.IsPending() {
+		return nil
 	}
 
-	b, err := yaml.Marshal(fields)
+	checksum, err := m.Checksum()
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	h := sha256.New()
-	if _, err := h.Write(b); err != nil {
-		return "", err
+	if stored != checksum {
+		return fmt.Errorf("%w: %s: recorded %s, current %s", ErrChecksumMismatch, m.Path, stored, checksum)
 	}
 
-	return fmt.Sprintf("%x", h.Sum(nil)), nil
+	return nil
 }
 
 // Migrate executes the operations registered to the migration.
 func (m *Migration) Migrate(ctx context.Context, db driver.Database) error {
-	for _, operation := range m.Operations {
+	return m.migrate(ctx, db, nil, nil, nil)
+}
+
+// migrate executes the operations registered to the migration, skipping the
+// operations whose index is in completed. If done is not nil, it is called with
+// the index of every operation executed successfully. If progress is not nil,
+// the resumable operations report their progress to the operationProgress it
+// returns for their index. If the migration has a transaction, the operations
+// are reported done only once the transaction is committed, as aborting it
+// reverts every operation.
+func (m *Migration) migrate(ctx context.Context, db driver.Database, completed []int, done func(index int) error, progress func(index int) *operationProgress) error {
+	if m.Transaction == nil {
+		return m.runOperations(ctx, db, completed, done, progress)
+	}
+
+	committed := make([]int, 0, len(m.Operations))
+	err := m.Transaction.run(ctx, db, func(ctx context.Context) error {
+		return m.runOperations(ctx, db, completed, func(index int) error {
+			committed = append(committed, index)
+			return nil
+		}, progress)
+	})
+	if err != nil || done == nil {
+		return err
+	}
+
+	for _, index := range committed {
+		if err := done(index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runOperations executes the operations registered to the migration like
+// migrate, without transaction.
+func (m *Migration) runOperations(ctx context.Context, db driver.Database, completed []int, done func(index int) error, progress func(index int) *operationProgress) error {
+	for i, operation := range m.Operations {
+		if slices.Contains(completed, i) {
+			continue
+		}
+
 		opFn, err := operation.GetOperationFn()
 		if err != nil {
 			return err
 		}
 
-		if err := opFn(ctx, db); err != nil {
+		opCtx := ctx
+		if progress != nil && slices.Contains(resumableKinds, operation.Kind) {
+			opCtx = withOperationProgress(ctx, progress(i))
+		}
+
+		if err := opFn(opCtx, db); err != nil {
 			return err
 		}
+
+		if done != nil {
+			if err := done(i); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// DownOperations returns the operations rolling back the migration. If the
+// migration has no down operations defined, they are derived by inverting the
+// operations of the migration in reverse order. If any of the operations is
+// irreversible, ErrIrreversibleMigration is returned listing the blocking
+// operations.
+func (m *Migration) DownOperations() ([]*Operation, error) {
+	if len(m.Down) > 0 {
+		return m.Down, nil
+	}
+
+	down := make([]*Operation, 0, len(m.Operations))
+	blocking := make([]string, 0)
+
+	for i, operation := range m.Operations {
+		inverse, err := operation.Inverse()
+		if err != nil {
+			// The blocking operations are listed without repeating the
+			// ErrIrreversibleOperation message for each of them.
+			reason := strings.TrimPrefix(err.Error(), ErrIrreversibleOperation.Error()+": ")
+			blocking = append(blocking, fmt.Sprintf("#%d %s", i+1, reason))
+			continue
+		}
+
+		down = append(down, inverse)
+	}
+
+	if len(blocking) > 0 {
+		return nil, fmt.Errorf("%w: blocking operations: %s", ErrIrreversibleMigration, strings.Join(blocking, ", "))
+	}
+
+	slices.Reverse(down)
+
+	return down, nil
+}
+
+// Rollback executes the down operations of the migration in the order they are
+// defined. If the migration has no down operations defined, the inverse of its
+// operations are executed instead. If the migration has a transaction, the
+// down operations run in a transaction of the same collections.
+func (m *Migration) Rollback(ctx context.Context, db driver.Database) error {
+	down, err := m.DownOperations()
+	if err != nil {
+		return err
+	}
+
+	rollback := func(ctx context.Context) error {
+		for _, operation := range down {
+			opFn, err := operation.GetOperationFn()
+			if err != nil {
+				return err
+			}
+
+			if err := opFn(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if m.Transaction != nil {
+		return m.Transaction.run(ctx, db, rollback)
+	}
+
+	return rollback(ctx)
+}
+
+// start marks the beginning of an execution or rollback of the migration.
+func (m *Migration) start() {
+	if m.record == nil {
+		m.record = new(MigrationItem)
+	}
+
+	m.record.StartedAt = time.Now().UTC()
+	m.record.FinishedAt = time.Time{}
+	m.record.Duration = 0
+	m.record.Error = ""
+}
+
+// finish marks the end of an execution or rollback of the migration, setting
+// its status. If err is not nil, its message is recorded.
+func (m *Migration) finish(status MigrationStatus, err error) {
+	if m.record == nil {
+		m.record = new(MigrationItem)
+	}
+
+	m.Status = status
+	m.record.FinishedAt = time.Now().UTC()
+	m.record.Duration = m.record.FinishedAt.Sub(m.record.StartedAt)
+
+	if err != nil {
+		m.record.Error = err.Error()
+	}
+
+	if status == MigrationStatusDone {
+		m.record.AppliedAt = m.record.FinishedAt
+	}
+}
+
 // saveMigration saves the migration to the database. The migration is saved
 // as a document in the given collection. If the document already exists, the
-// migration is updated.
-func saveMigration(ctx context.Context, coll driver.Collection, migration *Migration) {
+// migration is updated. If the migration was recorded by an earlier version
+// under its checksum, that document is replaced. The given version of arangom
+// is recorded along with the host and user saving the migration.
+func saveMigration(ctx context.Context, coll driver.Collection, migration *Migration, version string) error {
 	checksum, err := migration.Checksum()
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	exists, err := coll.DocumentExists(ctx, checksum)
+	return saveMigrationItem(ctx, coll, migration, version, checksum)
+}
+
+// saveMigrationItem saves the migration like saveMigration, recording the
+// given checksum.
+func saveMigrationItem(ctx context.Context, coll driver.Collection, migration *Migration, version string, checksum string) error {
+	key := migration.Key()
+	exists, err := coll.DocumentExists(ctx, key)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	item := &MigrationItem{
-		Key:       checksum,
-		Name:      migration.Name(),
-		Status:    migration.Status,
-		AppliedAt: time.Now(),
+	item := new(MigrationItem)
+	if migration.record != nil {
+		*item = *migration.record
 	}
 
+	item.Key = key
+	item.MigrationID = migration.ID
+	item.Name = migration.Name()
+	item.Checksum = checksum
+	item.Status = migration.Status
+	item.Version = version
+	item.Hostname = currentHostname()
+	item.User = currentUser()
+	item.OperationCount = len(migration.Operations)
+	item.CompletedOperations = migration.completed
+	item.Checkpoints = migration.checkpoints
+
 	if !exists {
-		if _, err := coll.CreateDocument(ctx, item); err != nil {
-			panic(err)
-		}
-		return
+		_, err = coll.CreateDocument(ctx, item)
+	} else {
+		_, err = coll.UpdateDocument(ctx, key, item)
 	}
 
-	if _, err := coll.UpdateDocument(ctx, checksum, item); err != nil {
-		panic(err)
+	if err != nil {
+		return err
 	}
+
+	if migration.record != nil && migration.record.Key != "" && migration.record.Key != key {
+		if _, err := coll.RemoveDocument(ctx, migration.record.Key); err != nil && !driver.IsNotFound(err) {
+			return err
+		}
+	}
+
+	migration.record = item
+
+	return nil
 }
 
+// legacyMigrationItemQuery returns the migration item of the named migration
+// created by an earlier version, which has no checksum field.
+const legacyMigrationItemQuery = `FOR doc IN @@collection FILTER doc.name == @name AND doc.checksum == null LIMIT 1 RETURN doc`
+
 // fetchMigrationStatus fetches the status of the migration from the database.
-func fetchMigrationStatus(ctx context.Context, coll driver.Collection, migration *Migration) {
+// If the migration has no document keyed by its key, the document keyed by
+// its checksum, created by earlier versions, is looked up. If the migration
+// changed since an earlier version executed it, neither key matches, so the
+// document is looked up by the name of the migration.
+func fetchMigrationStatus(ctx context.Context, coll driver.Collection, migration *Migration) error {
 	checksum, err := migration.Checksum()
 	if err != nil {
-		panic(err)
+		return err
+	}
+
+	migration.Status = MigrationStatusMissing
+	migration.record = nil
+	migration.completed = nil
+	migration.checkpoints = nil
+
+	for _, key := range []string{migration.Key(), checksum} {
+		exists, err := coll.DocumentExists(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			continue
+		}
+
+		item := new(MigrationItem)
+		if _, err := coll.ReadDocument(ctx, key, item); err != nil {
+			return err
+		}
+
+		// The legacy documents are keyed by the checksum itself.
+		if item.Checksum == "" && key == checksum {
+			item.Checksum = checksum
+		}
+
+		migration.setRecord(item)
+
+		return nil
+	}
+
+	if migration.Name() == "" {
+		return nil
+	}
+
+	item, err := fetchLegacyMigrationItem(ctx, coll, migration.Name())
+	if err != nil || item == nil {
+		return err
 	}
 
-	exists, err := coll.DocumentExists(ctx, checksum)
+	// The legacy documents are keyed by the checksum the migration had when
+	// it was executed.
+	item.Checksum = item.Key
+	migration.setRecord(item)
+
+	return nil
+}
+
+// fetchLegacyMigrationItem returns the migration item of the named migration
+// created by an earlier version, or nil if there is none.
+func fetchLegacyMigrationItem(ctx context.Context, coll driver.Collection, name string) (*MigrationItem, error) {
+	cursor, err := coll.Database().Query(ctx, legacyMigrationItemQuery, map[string]any{
+		"@collection": coll.Name(),
+		"name":        name,
+	})
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	if !exists {
-		migration.Status = MigrationStatusMissing
-		return
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	if !cursor.HasMore() {
+		return nil, nil
 	}
 
 	item := new(MigrationItem)
-	if _, err := coll.ReadDocument(ctx, checksum, item); err != nil {
-		panic(err)
+	if _, err := cursor.ReadDocument(ctx, item); err != nil {
+		if driver.IsNoMoreDocuments(err) {
+			return nil, nil
+		}
+
+		return nil, err
 	}
 
-	migration.Status = item.Status
+	return item, nil
+}
+
+// setRecord sets the status and the progress of the migration from its
+// migration item.
+func (m *Migration) setRecord(item *MigrationItem) {
+	m.Status = item.Status
+	m.record = item
+	m.completed = item.CompletedOperations
+	m.checkpoints = item.Checkpoints
 }
@@ -2,7 +2,9 @@ package arangom
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/arangodb/go-driver"
@@ -41,6 +43,24 @@ func TestMigrationStatus_UnmarshalJSON(t *testing.T) {
 			status: []byte(`"failed"`),
 			want:   MigrationStatusFailed,
 		},
+		{
+			name:   "rolled back",
+			s:      new(MigrationStatus),
+			status: []byte(`"rolledBack"`),
+			want:   MigrationStatusRolledBack,
+		},
+		{
+			name:   "rolling back",
+			s:      new(MigrationStatus),
+			status: []byte(`"rollingBack"`),
+			want:   MigrationStatusRollingBack,
+		},
+		{
+			name:   "rollback failed",
+			s:      new(MigrationStatus),
+			status: []byte(`"rollbackFailed"`),
+			want:   MigrationStatusRollbackFailed,
+		},
 		{
 			name:    "invalid",
 			s:       new(MigrationStatus),
@@ -92,6 +112,21 @@ func TestMigrationStatus_MarshalJSON(t *testing.T) {
 			s:    MigrationStatusFailed,
 			want: []byte(`"failed"`),
 		},
+		{
+			name: "rolled back",
+			s:    MigrationStatusRolledBack,
+			want: []byte(`"rolledBack"`),
+		},
+		{
+			name: "rolling back",
+			s:    MigrationStatusRollingBack,
+			want: []byte(`"rollingBack"`),
+		},
+		{
+			name: "rollback failed",
+			s:    MigrationStatusRollbackFailed,
+			want: []byte(`"rollbackFailed"`),
+		},
 		{
 			name:    "invalid",
 			s:       MigrationStatus(-1),
@@ -150,6 +185,34 @@ func TestMigration_Name(t *testing.T) {
 			},
 			want: "name",
 		},
+		{
+			name: "get name from yml file name",
+			migration: &Migration{
+				Path: "name.yml",
+			},
+			want: "name",
+		},
+		{
+			name: "get name from json file name",
+			migration: &Migration{
+				Path: "path/to/name.json",
+			},
+			want: "name",
+		},
+		{
+			name: "get name from aql file name",
+			migration: &Migration{
+				Path: "path/to/name.aql",
+			},
+			want: "name",
+		},
+		{
+			name: "get name from file name with unknown extension",
+			migration: &Migration{
+				Path: "name.txt",
+			},
+			want: "name.txt",
+		},
 		{
 			name: "get name from file name with multiple dots",
 			migration: &Migration{
@@ -170,6 +233,215 @@ func TestMigration_Name(t *testing.T) {
 	}
 }
 
+func TestMigration_Key(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration *Migration
+		want      string
+	}{
+		{
+			name: "get key from ID",
+			migration: &Migration{
+				ID:   123,
+				Path: "name.yaml",
+			},
+			want: "123",
+		},
+		{
+			name: "get key from name without ID",
+			migration: &Migration{
+				Path: "name.yaml",
+			},
+			want: "name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.migration.Key(); got != tt.want {
+				t.Errorf("Migration.Key() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigration_VerifyChecksum(t *testing.T) {
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+	tests := []struct {
+		name      string
+		migration *Migration
+		wantErr   error
+	}{
+		{
+			name: "verify unchanged migration",
+			migration: &Migration{
+				ID:     123,
+				Status: MigrationStatusDone,
+				record: &MigrationItem{Checksum: checksum},
+			},
+		},
+		{
+			name: "verify changed migration",
+			migration: &Migration{
+				ID:     123,
+				Path:   "123_name.yaml",
+				Status: MigrationStatusDone,
+				record: &MigrationItem{Checksum: "old"},
+			},
+			wantErr: ErrChecksumMismatch,
+		},
+		{
+			name: "verify changed pending migration",
+			migration: &Migration{
+				ID:     123,
+				Status: MigrationStatusRolledBack,
+				record: &MigrationItem{Checksum: "old"},
+			},
+		},
+		{
+			name: "verify migration without record",
+			migration: &Migration{
+				ID:     123,
+				Status: MigrationStatusMissing,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.migration.VerifyChecksum()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Migration.VerifyChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// mockLegacyRecords registers the lookup of the migration items created by
+// earlier versions by name, returning the given documents.
+func mockLegacyRecords(coll *MockArangoCollection, docs ...any) {
+	db := new(MockArangoDB)
+	db.On("Query", mock.Anything, legacyMigrationItemQuery, mock.Anything).Return(mockCursor(docs...), nil)
+	coll.On("Name").Return("test")
+	coll.On("Database").Return(db)
+}
+
+func TestFetchMigrationStatus(t *testing.T) {
+	ctx := context.Background()
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+	tests := []struct {
+		name         string
+		coll         func() driver.Collection
+		wantStatus   MigrationStatus
+		wantChecksum string
+	}{
+		{
+			name: "fetch status by key",
+			coll: func() driver.Collection {
+				coll := new(MockArangoCollection)
+				coll.On("DocumentExists", ctx, "123").Return(true, nil)
+				coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+					Key:      "123",
+					Checksum: "old",
+					Status:   MigrationStatusDone,
+				}, driver.DocumentMeta{}, nil)
+				return coll
+			},
+			wantStatus:   MigrationStatusDone,
+			wantChecksum: "old",
+		},
+		{
+			name: "fetch status recorded by checksum",
+			coll: func() driver.Collection {
+				coll := new(MockArangoCollection)
+				coll.On("DocumentExists", ctx, "123").Return(false, nil)
+				coll.On("DocumentExists", ctx, checksum).Return(true, nil)
+				coll.On("ReadDocument", ctx, checksum, mock.Anything).Return(&MigrationItem{
+					Key:    checksum,
+					Status: MigrationStatusDone,
+				}, driver.DocumentMeta{}, nil)
+				return coll
+			},
+			wantStatus:   MigrationStatusDone,
+			wantChecksum: checksum,
+		},
+		{
+			name: "fetch status recorded by name",
+			coll: func() driver.Collection {
+				coll := new(MockArangoCollection)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+				mockLegacyRecords(coll, &MigrationItem{
+					Key:    "old",
+					Name:   "123_name",
+					Status: MigrationStatusDone,
+				})
+				return coll
+			},
+			wantStatus:   MigrationStatusDone,
+			wantChecksum: "old",
+		},
+		{
+			name: "fetch status of missing migration",
+			coll: func() driver.Collection {
+				coll := new(MockArangoCollection)
+				coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+				mockLegacyRecords(coll)
+				return coll
+			},
+			wantStatus: MigrationStatusMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			migration := &Migration{ID: 123, Path: "123_name.yaml"}
+			if err := fetchMigrationStatus(ctx, tt.coll(), migration); err != nil {
+				t.Fatalf("fetchMigrationStatus() error = %v", err)
+			}
+
+			if migration.Status != tt.wantStatus {
+				t.Errorf("fetchMigrationStatus() status = %v, want %v", migration.Status, tt.wantStatus)
+			}
+
+			if got := migration.StoredChecksum(); got != tt.wantChecksum {
+				t.Errorf("fetchMigrationStatus() checksum = %v, want %v", got, tt.wantChecksum)
+			}
+		})
+	}
+}
+
+func TestSaveMigration_replacesChecksumKey(t *testing.T) {
+	ctx := context.Background()
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+	coll := new(MockArangoCollection)
+	coll.On("DocumentExists", ctx, "123").Return(false, nil)
+	coll.On("CreateDocument", ctx, mock.MatchedBy(func(item *MigrationItem) bool {
+		return item.Key == "123" && item.MigrationID == 123 && item.Checksum == checksum
+	})).Return(driver.DocumentMeta{}, nil)
+	coll.On("RemoveDocument", ctx, checksum).Return(driver.DocumentMeta{}, nil)
+
+	migration := &Migration{
+		ID:     123,
+		Status: MigrationStatusDone,
+		record: &MigrationItem{Key: checksum, Checksum: checksum},
+	}
+
+	if err := saveMigration(ctx, coll, migration, "v1.0.0"); err != nil {
+		t.Fatalf("saveMigration() error = %v", err)
+	}
+
+	coll.AssertExpectations(t)
+}
+
 func TestMigration_Checksum(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -202,6 +474,21 @@ func TestMigration_Checksum(t *testing.T) {
 			},
 			want: "78f5c1046a6d4edbedd55a4780de9211e8577592887521e1ed862cb6a501c52b",
 		},
+		{
+			name: "get checksum with imported documents",
+			migration: &Migration{
+				Path: "name.yaml",
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindDocumentsImport,
+						Collection: "countries",
+						Options:    map[string]any{"file": "countries.jsonl"},
+						content:    []byte("{\"_key\": \"hu\"}\n"),
+					},
+				},
+			},
+			want: "463356d16d27f5c9090961d8b669fc1e8788fb58551953170a4bd11bcc920913",
+		},
 	}
 
 	for _, tt := range tests {
@@ -325,3 +612,344 @@ func TestMigration_Migrate(t *testing.T) {
 		})
 	}
 }
+
+func TestMigrationStatus_String(t *testing.T) {
+	tests := []struct {
+		name string
+		s    MigrationStatus
+		want string
+	}{
+		{
+			name: "done",
+			s:    MigrationStatusDone,
+			want: "done",
+		},
+		{
+			name: "rolled back",
+			s:    MigrationStatusRolledBack,
+			want: "rolledBack",
+		},
+		{
+			name: "rollback failed",
+			s:    MigrationStatusRollbackFailed,
+			want: "rollbackFailed",
+		},
+		{
+			name: "unknown",
+			s:    MigrationStatus(-1),
+			want: "MigrationStatus(-1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.s.String(); got != tt.want {
+				t.Errorf("MigrationStatus.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrationStatus_IsPending(t *testing.T) {
+	tests := []struct {
+		name string
+		s    MigrationStatus
+		want bool
+	}{
+		{
+			name: "missing",
+			s:    MigrationStatusMissing,
+			want: true,
+		},
+		{
+			name: "running",
+			s:    MigrationStatusRunning,
+			want: false,
+		},
+		{
+			name: "done",
+			s:    MigrationStatusDone,
+			want: false,
+		},
+		{
+			name: "failed",
+			s:    MigrationStatusFailed,
+			want: false,
+		},
+		{
+			name: "rolled back",
+			s:    MigrationStatusRolledBack,
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.s.IsPending(); got != tt.want {
+				t.Errorf("MigrationStatus.IsPending() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigration_Rollback(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name      string
+		migration *Migration
+		args      args
+		wantErr   bool
+	}{
+		{
+			name: "rollback with no operations",
+			migration: &Migration{
+				Path: "migration.yaml",
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+		},
+		{
+			name: "rollback with derived down operations",
+			migration: &Migration{
+				Path: "migration.yaml",
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "test",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("Remove", context.Background()).Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+					return db
+				}(),
+			},
+		},
+		{
+			name: "rollback irreversible migration",
+			migration: &Migration{
+				Path: "migration.yaml",
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionDelete,
+						Collection: "test",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollback with down operations",
+			migration: &Migration{
+				Path: "migration.yaml",
+				Down: []*Operation{
+					{
+						Kind:       OperationKindCollectionDelete,
+						Collection: "test",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("Remove", context.Background()).Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+					return db
+				}(),
+			},
+		},
+		{
+			name: "rollback with operation error",
+			migration: &Migration{
+				Path: "migration.yaml",
+				Down: []*Operation{
+					{
+						Kind:       OperationKindCollectionDelete,
+						Collection: "test",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(nil, fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollback with invalid operation kind",
+			migration: &Migration{
+				Path: "migration.yaml",
+				Down: []*Operation{
+					{
+						Kind: OperationKind(0),
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.migration.Rollback(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("Migration.Rollback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMigration_DownOperations(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration *Migration
+		want      []*Operation
+		wantErr   error
+		wantMsg   string
+	}{
+		{
+			name: "explicit down operations",
+			migration: &Migration{
+				Operations: []*Operation{
+					{
+						Kind: OperationKindAQLExecute,
+					},
+				},
+				Down: []*Operation{
+					{
+						Kind:       OperationKindCollectionDelete,
+						Collection: "test",
+					},
+				},
+			},
+			want: []*Operation{
+				{
+					Kind:       OperationKindCollectionDelete,
+					Collection: "test",
+				},
+			},
+		},
+		{
+			name: "derived down operations in reverse order",
+			migration: &Migration{
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+					},
+					{
+						Kind:       OperationKindPersistentIndexCreate,
+						Collection: "users",
+						Options: map[string]any{
+							"name":   "idx_email",
+							"fields": []string{"email"},
+						},
+					},
+				},
+			},
+			want: []*Operation{
+				{
+					Kind:       OperationKindIndexDelete,
+					Collection: "users",
+					Options: map[string]any{
+						"name": "idx_email",
+					},
+				},
+				{
+					Kind:       OperationKindCollectionDelete,
+					Collection: "users",
+				},
+			},
+		},
+		{
+			name: "irreversible operations",
+			migration: &Migration{
+				Operations: []*Operation{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+					},
+					{
+						Kind: OperationKindAQLExecute,
+					},
+					{
+						Kind:       OperationKindHashIndexCreate,
+						Collection: "users",
+					},
+				},
+			},
+			wantErr: ErrIrreversibleMigration,
+			wantMsg: `irreversible migration: blocking operations: #2 executeAQL, #3 createHashIndex (missing "name" option)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.migration.DownOperations()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Migration.DownOperations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Migration.DownOperations() error = %v, want %v", err, tt.wantMsg)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Migration.DownOperations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrationError(t *testing.T) {
+	t.Parallel()
+
+	cause := fmt.Errorf("network error")
+	var err error = &MigrationError{
+		ID:    123,
+		Phase: MigrationPhaseFetchStatus,
+		Err:   cause,
+	}
+
+	if got, want := err.Error(), "migration 123: fetch status: network error"; got != want {
+		t.Errorf("MigrationError.Error() = %v, want %v", got, want)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Errorf("MigrationError.Unwrap() = %v, want %v", errors.Unwrap(err), cause)
+	}
+}
@@ -1,9 +1,13 @@
 package arangom
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/arangodb/go-driver"
 	"github.com/pkg/errors"
@@ -35,19 +39,39 @@ const (
 	OperationKindIndexDelete                                    // operation to delete an index
 	OperationKindAnalyzerCreate                                 // operation to delete an index
 	OperationKindAnalyzerDelete                                 // operation to delete an index
+	OperationKindGo                                             // operation to run the function of a Go migration
+	OperationKindCollectionRename                               // operation to rename a collection
+	OperationKindCollectionTruncate                             // operation to truncate a collection
+	OperationKindDocumentsImport                                // operation to import documents from a file
+	OperationKindDocumentsInsert                                // operation to insert documents
+	OperationKindDocumentsUpsert                                // operation to insert or update documents
+	OperationKindDocumentsReplace                               // operation to replace documents
+	OperationKindDocumentsRemove                                // operation to remove documents
+	OperationKindAQLBackfill                                    // operation to execute an AQL query in batches
 )
 
 var (
 	// ErrInvalidOperationKind is returned when an invalid operation kind is
 	// specified.
 	ErrInvalidOperationKind = fmt.Errorf("invalid operation kind")
+	// ErrIrreversibleOperation is returned when the inverse of an operation
+	// cannot be derived.
+	ErrIrreversibleOperation = fmt.Errorf("irreversible operation")
 
 	// operationMap is a map of operation names to operation kinds.
 	operationMap = map[string]OperationKind{
 		"executeAQL":            OperationKindAQLExecute,
+		"backfillAQL":           OperationKindAQLBackfill,
 		"createCollection":      OperationKindCollectionCreate,
 		"updateCollection":      OperationKindCollectionUpdate,
 		"deleteCollection":      OperationKindCollectionDelete,
+		"renameCollection":      OperationKindCollectionRename,
+		"truncateCollection":    OperationKindCollectionTruncate,
+		"importDocuments":       OperationKindDocumentsImport,
+		"insertDocuments":       OperationKindDocumentsInsert,
+		"upsertDocuments":       OperationKindDocumentsUpsert,
+		"replaceDocuments":      OperationKindDocumentsReplace,
+		"removeDocuments":       OperationKindDocumentsRemove,
 		"createGraph":           OperationKindGraphCreate,
 		"addVertexToGraph":      OperationKindGraphAddVertex,
 		"removeVertexFromGraph": OperationKindGraphRemoveVertex,
@@ -73,9 +97,17 @@ var (
 	// operationKindMap is a map of operation kinds to operations.
 	operationKindMap = map[OperationKind]func(o *Operation) OperationFn{
 		OperationKindAQLExecute:            ExecuteAQLOperation,
+		OperationKindAQLBackfill:           BackfillAQLOperation,
 		OperationKindCollectionCreate:      CreateCollectionOperation,
 		OperationKindCollectionUpdate:      UpdateCollectionOperation,
 		OperationKindCollectionDelete:      DeleteCollectionOperation,
+		OperationKindCollectionRename:      RenameCollectionOperation,
+		OperationKindCollectionTruncate:    TruncateCollectionOperation,
+		OperationKindDocumentsImport:       ImportDocumentsOperation,
+		OperationKindDocumentsInsert:       InsertDocumentsOperation,
+		OperationKindDocumentsUpsert:       UpsertDocumentsOperation,
+		OperationKindDocumentsReplace:      ReplaceDocumentsOperation,
+		OperationKindDocumentsRemove:       RemoveDocumentsOperation,
 		OperationKindGraphCreate:           CreateGraphOperation,
 		OperationKindGraphAddVertex:        AddVertexOperation,
 		OperationKindGraphRemoveVertex:     RemoveVertexOperation,
@@ -96,6 +128,65 @@ var (
 		OperationKindIndexDelete:           DeleteIndexOperation,
 		OperationKindAnalyzerCreate:        CreateAnalyzerOperation,
 		OperationKindAnalyzerDelete:        DeleteAnalyzerOperation,
+		OperationKindGo:                    goOperation,
+	}
+
+	// operationInverseMap is a map of operation kinds to functions deriving
+	// the inverse of an operation. Operation kinds missing from the map are
+	// irreversible.
+	operationInverseMap = map[OperationKind]func(o *Operation) (*Operation, error){
+		OperationKindCollectionCreate:      inverseOperation(OperationKindCollectionDelete),
+		OperationKindCollectionRename:      inverseRenameCollection,
+		OperationKindDocumentsInsert:       inverseInsertDocuments,
+		OperationKindGraphCreate:           inverseOperation(OperationKindGraphDelete),
+		OperationKindGraphAddVertex:        inverseOperation(OperationKindGraphRemoveVertex, "collection"),
+		OperationKindGraphRemoveVertex:     inverseOperation(OperationKindGraphAddVertex, "collection"),
+		OperationKindGraphAddEdge:          inverseOperation(OperationKindGraphRemoveEdge, "collection"),
+		OperationKindViewCreate:            inverseOperation(OperationKindViewDelete),
+		OperationKindFulltextIndexCreate:   inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindGeoSpatialIndexCreate: inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindHashIndexCreate:       inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindInvertedIndexCreate:   inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindPersistentIndexCreate: inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindSkipListIndexCreate:   inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindTTLIndexCreate:        inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindZKDIndexCreate:        inverseOperation(OperationKindIndexDelete, "name"),
+		OperationKindAnalyzerCreate:        inverseOperation(OperationKindAnalyzerDelete, "name"),
+	}
+
+	// operationOptionsMap is a map of operation kinds to functions returning a
+	// pointer to the options the operation options are converted to. Operation
+	// kinds missing from the map have no options.
+	operationOptionsMap = map[OperationKind]func() any{
+		OperationKindAQLExecute:            newOperationOptions[aqlOpts],
+		OperationKindAQLBackfill:           newOperationOptions[backfillAQLOpts],
+		OperationKindCollectionCreate:      newOperationOptions[driver.CreateCollectionOptions],
+		OperationKindCollectionUpdate:      newOperationOptions[driver.SetCollectionPropertiesOptions],
+		OperationKindCollectionRename:      newOperationOptions[renameCollectionOpts],
+		OperationKindDocumentsImport:       newOperationOptions[importDocumentsOpts],
+		OperationKindDocumentsInsert:       newOperationOptions[documentsOpts],
+		OperationKindDocumentsUpsert:       newOperationOptions[keyedDocumentsOpts],
+		OperationKindDocumentsReplace:      newOperationOptions[keyedDocumentsOpts],
+		OperationKindDocumentsRemove:       newOperationOptions[removeDocumentsOpts],
+		OperationKindGraphCreate:           newOperationOptions[driver.CreateGraphOptions],
+		OperationKindGraphAddVertex:        newOperationOptions[addVertexOpts],
+		OperationKindGraphRemoveVertex:     newOperationOptions[removeVertexOpts],
+		OperationKindGraphAddEdge:          newOperationOptions[addEdgeOpts],
+		OperationKindGraphRemoveEdge:       newOperationOptions[removeEdgeOpts],
+		OperationKindViewCreate:            newOperationOptions[driver.ArangoSearchViewProperties],
+		OperationKindViewUpdate:            newOperationOptions[driver.ArangoSearchViewProperties],
+		OperationKindFulltextIndexCreate:   newOperationOptions[fulltextIndexOpts],
+		OperationKindGeoSpatialIndexCreate: newOperationOptions[geoSpatialIndexOpts],
+		OperationKindHashIndexCreate:       newOperationOptions[hashIndexOpts],
+		OperationKindInvertedIndexCreate:   newOperationOptions[driver.InvertedIndexOptions],
+		OperationKindPersistentIndexCreate: newOperationOptions[persistentIndexOpts],
+		OperationKindSkipListIndexCreate:   newOperationOptions[skipListIndexOpts],
+		OperationKindTTLIndexCreate:        newOperationOptions[ttlIndexOpts],
+		OperationKindZKDIndexCreate:        newOperationOptions[zkdIndexOpts],
+		OperationKindIndexDelete:           newOperationOptions[deleteIndexOpts],
+		OperationKindAnalyzerCreate:        newOperationOptions[createAnalyzerOpts],
+		OperationKindAnalyzerDelete:        newOperationOptions[deleteAnalyzerOpts],
+		OperationKindGo:                    newOperationOptions[goOpts],
 	}
 )
 
@@ -105,6 +196,23 @@ type OperationFn func(ctx context.Context, db driver.Database) error
 // OperationKind is the kind of operation to run.
 type OperationKind int
 
+// String returns the name of the operation kind as used in migration files.
+// The operations of Go migrations cannot be used in migration files, they are
+// named "go".
+func (o OperationKind) String() string {
+	if o == OperationKindGo {
+		return "go"
+	}
+
+	for name, kind := range operationMap {
+		if kind == o {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("OperationKind(%d)", int(o))
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (o *OperationKind) UnmarshalYAML(value *yaml.Node) error {
 	if kind, ok := operationMap[value.Value]; ok {
@@ -120,6 +228,12 @@ type Operation struct {
 	Kind       OperationKind  `yaml:"kind"`
 	Collection string         `yaml:"collection"`
 	Options    map[string]any `yaml:"options"`
+
+	// fn is the function of the operation of a Go migration.
+	fn OperationFn
+	// content is the content of the document file of an importDocuments
+	// operation, read when the migration is loaded.
+	content []byte
 }
 
 // GetOperationFn returns the operation function for the operation kind.
@@ -131,28 +245,239 @@ func (o *Operation) GetOperationFn() (OperationFn, error) {
 	return nil, ErrInvalidOperationKind
 }
 
+// Inverse returns the operation reverting the changes made by the operation.
+// If the operation kind has no inverse, or the operation is missing options
+// required to derive it, ErrIrreversibleOperation is returned.
+func (o *Operation) Inverse() (*Operation, error) {
+	inverseFn, ok := operationInverseMap[o.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIrreversibleOperation, o.Kind)
+	}
+
+	inverse, err := inverseFn(o)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrIrreversibleOperation, o.Kind, err.Error())
+	}
+
+	return inverse, nil
+}
+
+// inverseOperation returns a function that derives an operation of the given
+// kind on the same collection. The given option keys are copied from the
+// original operation and they must be set.
+func inverseOperation(kind OperationKind, optionKeys ...string) func(o *Operation) (*Operation, error) {
+	return func(o *Operation) (*Operation, error) {
+		inverse := &Operation{
+			Kind:       kind,
+			Collection: o.Collection,
+		}
+
+		for _, key := range optionKeys {
+			value, ok := o.Options[key]
+			if !ok || value == nil || strings.TrimSpace(fmt.Sprint(value)) == "" {
+				return nil, fmt.Errorf("missing %q option", key)
+			}
+
+			if inverse.Options == nil {
+				inverse.Options = make(map[string]any, len(optionKeys))
+			}
+
+			inverse.Options[key] = value
+		}
+
+		return inverse, nil
+	}
+}
+
+// inverseRenameCollection derives the operation renaming the collection back
+// to its original name.
+func inverseRenameCollection(o *Operation) (*Operation, error) {
+	newName, ok := o.Options["newName"].(string)
+	if !ok || strings.TrimSpace(newName) == "" {
+		return nil, fmt.Errorf("missing %q option", "newName")
+	}
+
+	return &Operation{
+		Kind:       OperationKindCollectionRename,
+		Collection: newName,
+		Options:    map[string]any{"newName": o.Collection},
+	}, nil
+}
+
+// inverseInsertDocuments derives the operation removing the inserted
+// documents by their key.
+func inverseInsertDocuments(o *Operation) (*Operation, error) {
+	opts := keyedDocumentsOpts{}
+	if err := convertToOperationOptions(o.Options, &opts); err != nil {
+		return nil, err
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Operation{
+		Kind:       OperationKindDocumentsRemove,
+		Collection: o.Collection,
+		Options:    map[string]any{"keys": opts.keys()},
+	}, nil
+}
+
+// ResolveOptions returns the options of the operation converted to the options
+// used when running the operation. If the operation kind has no options, nil
+// is returned. Options that convert but have invalid values are rejected too.
+func (o *Operation) ResolveOptions() (any, error) {
+	if _, ok := operationKindMap[o.Kind]; !ok {
+		return nil, ErrInvalidOperationKind
+	}
+
+	newOpts, ok := operationOptionsMap[o.Kind]
+	if !ok {
+		return nil, nil
+	}
+
+	opts := newOpts()
+	if err := convertToOperationOptions(o.Options, opts); err != nil {
+		return nil, err
+	}
+
+	if v, ok := opts.(interface{ validate() error }); ok {
+		if err := v.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+// newOperationOptions returns a pointer to new, empty operation options.
+func newOperationOptions[T any]() any {
+	return new(T)
+}
+
 // convertToOperationOptions converts a map of options to a struct using JSON.
 // This is used to convert the options from the YAML file to the options for
 // the ArangoDB driver. Since the ArangoDB driver uses a JSON tag for the
 // options, we can use JSON to convert the options from the YAML file to the
-// options for the ArangoDB driver.
+// options for the ArangoDB driver. Unknown options are rejected instead of
+// being dropped silently.
 func convertToOperationOptions(opts map[string]any, dst any) error {
 	b, err := json.Marshal(opts)
 	if err != nil {
 		return err
 	}
 
-	return json.Unmarshal(b, dst)
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(dst)
+}
+
+// aqlOpts are the options of the executeAQL operation.
+type aqlOpts struct {
+	Query    string         `json:"query"`
+	BindVars map[string]any `json:"bindVars"`
+}
+
+// renameCollectionOpts are the options of the renameCollection operation.
+type renameCollectionOpts struct {
+	NewName string `json:"newName"`
+}
+
+// addVertexOpts are the options of the addVertexToGraph operation.
+type addVertexOpts struct {
+	driver.CreateVertexCollectionOptions
+	Collection string `json:"collection"`
+}
+
+// removeVertexOpts are the options of the removeVertexFromGraph operation.
+type removeVertexOpts struct {
+	Collection string `json:"collection"`
+}
+
+// addEdgeOpts are the options of the addEdgeToGraph operation.
+type addEdgeOpts struct {
+	driver.CreateEdgeCollectionOptions
+	Collection  string                   `json:"collection"`
+	Constraints driver.VertexConstraints `json:"constraints"`
+}
+
+// removeEdgeOpts are the options of the removeEdgeFromGraph operation.
+type removeEdgeOpts struct {
+	Collection string `json:"collection"`
+}
+
+// fulltextIndexOpts are the options of the createFulltextIndex operation.
+type fulltextIndexOpts struct {
+	driver.EnsureFullTextIndexOptions
+	Fields []string `json:"fields"`
+}
+
+// geoSpatialIndexOpts are the options of the createGeoSpatialIndex operation.
+type geoSpatialIndexOpts struct {
+	driver.EnsureGeoIndexOptions
+	Fields []string `json:"fields"`
+}
+
+// hashIndexOpts are the options of the createHashIndex operation.
+type hashIndexOpts struct {
+	driver.EnsureHashIndexOptions
+	Fields []string `json:"fields"`
+}
+
+// persistentIndexOpts are the options of the createPersistentIndex operation.
+type persistentIndexOpts struct {
+	driver.EnsurePersistentIndexOptions
+	Fields []string `json:"fields"`
+}
+
+// skipListIndexOpts are the options of the createSkipListIndex operation.
+type skipListIndexOpts struct {
+	driver.EnsureSkipListIndexOptions
+	Fields []string `json:"fields"`
+}
+
+// ttlIndexOpts are the options of the createTTLIndex operation.
+type ttlIndexOpts struct {
+	driver.EnsureTTLIndexOptions
+	Field       string `json:"field"`
+	ExpireAfter int    `json:"expireAfter"`
+}
+
+// zkdIndexOpts are the options of the createZKDIndex operation.
+type zkdIndexOpts struct {
+	driver.EnsureZKDIndexOptions
+	Fields []string `json:"fields"`
+}
+
+// deleteIndexOpts are the options of the deleteIndex operation.
+type deleteIndexOpts struct {
+	Name string `json:"name"`
+}
+
+// createAnalyzerOpts are the options of the createAnalyzer operation. The
+// operation sends the options as is, the struct only describes them.
+type createAnalyzerOpts struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Features   []string       `json:"features,omitempty"`
+}
+
+// deleteAnalyzerOpts are the options of the deleteAnalyzer operation.
+type deleteAnalyzerOpts struct {
+	Name  string `json:"name"`
+	Force bool   `json:"force"`
+}
+
+// goOpts are the options of the operation of a Go migration.
+type goOpts struct {
+	Version string `json:"version"`
 }
 
 // ExecuteAQLOperation executes an AQL query.
 func ExecuteAQLOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type aqlOpts struct {
-			Query    string         `json:"query"`
-			BindVars map[string]any `json:"bindVars"`
-		}
-
 		opts := aqlOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -164,6 +489,102 @@ func ExecuteAQLOperation(o *Operation) OperationFn {
 	}
 }
 
+// BackfillAQLOperation executes an AQL query in batches bound to @batch and
+// @lastKey, the query returning the processed keys in ascending order.
+func BackfillAQLOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := backfillAQLOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		if err := opts.validate(); err != nil {
+			return err
+		}
+
+		batchSize := opts.BatchSize
+		if batchSize == 0 {
+			batchSize = DefaultBackfillBatchSize
+		}
+
+		bindVars := make(map[string]any, len(opts.BindVars)+1)
+		maps.Copy(bindVars, opts.BindVars)
+
+		if _, ok := bindVars["@collection"]; !ok && o.Collection != "" && strings.Contains(opts.Query, "@@collection") {
+			bindVars["@collection"] = o.Collection
+		}
+
+		progress := operationProgressFromContext(ctx)
+
+		subject := o.Collection
+		if subject == "" {
+			subject = "query"
+		}
+
+		lastKey := progress.checkpoint
+		if lastKey != "" {
+			progress.logger.Infof("resuming backfill of %s after key %q", subject, lastKey)
+		}
+
+		for processed := 0; ; {
+			batchVars := maps.Clone(bindVars)
+			batchVars["batch"] = batchSize
+			batchVars["lastKey"] = lastKey
+
+			keys, err := queryKeys(ctx, db, opts.Query, batchVars)
+			if err != nil {
+				return err
+			}
+
+			if len(keys) == 0 {
+				return nil
+			}
+
+			// AQL may order the keys differently than Go, so the backfill is
+			// only considered stalled if the last processed key is returned
+			// again.
+			if lastKey != "" && slices.Contains(keys, lastKey) {
+				return fmt.Errorf("%w: %s: the query returned the last processed key %q again", ErrBackfillStalled, subject, lastKey)
+			}
+
+			lastKey = keys[len(keys)-1]
+			processed += len(keys)
+
+			if err := progress.save(lastKey); err != nil {
+				return err
+			}
+
+			progress.logger.Infof("backfill of %s processed %d documents, last key %q", subject, processed, lastKey)
+
+			if len(keys) < batchSize {
+				return nil
+			}
+		}
+	}
+}
+
+// queryKeys executes the query and returns the keys it returns.
+func queryKeys(ctx context.Context, db driver.Database, query string, bindVars map[string]any) ([]string, error) {
+	cursor, err := db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cursor.Close() }()
+
+	keys := make([]string, 0)
+	for {
+		var key string
+		if _, err := cursor.ReadDocument(ctx, &key); driver.IsNoMoreDocuments(err) {
+			return keys, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+}
+
 // CreateCollectionOperation creates a collection.
 func CreateCollectionOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
@@ -206,6 +627,194 @@ func DeleteCollectionOperation(o *Operation) OperationFn {
 	}
 }
 
+// RenameCollectionOperation renames a collection.
+func RenameCollectionOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := renameCollectionOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		return coll.Rename(ctx, opts.NewName)
+	}
+}
+
+// TruncateCollectionOperation removes every document of a collection, keeping
+// its indexes.
+func TruncateCollectionOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		return coll.Truncate(ctx)
+	}
+}
+
+// ImportDocumentsOperation creates the documents of the document file of the
+// operation in batches. Documents with an existing key are rejected, updated,
+// replaced or ignored as set by the onDuplicate option. The documents rejected
+// by the database are reported in the returned error.
+func ImportDocumentsOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := importDocumentsOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		if err := opts.validate(); err != nil {
+			return err
+		}
+
+		if o.content == nil {
+			return fmt.Errorf("%w: %s is not loaded", ErrInvalidDocuments, opts.File)
+		}
+
+		documents, err := parseDocuments(opts.File, o.content)
+		if err != nil {
+			return err
+		}
+
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		if mode := onDuplicateModes[opts.OnDuplicate]; mode != "" {
+			ctx = driver.WithOverwriteMode(ctx, mode)
+		}
+
+		batchSize := opts.BatchSize
+		if batchSize == 0 {
+			batchSize = DefaultImportBatchSize
+		}
+
+		for offset := 0; offset < len(documents); offset += batchSize {
+			batch := documents[offset:min(offset+batchSize, len(documents))]
+
+			_, errs, err := coll.CreateDocuments(ctx, batch)
+			if err != nil {
+				return err
+			}
+
+			if err := documentErrors(o.Collection, offset, errs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// InsertDocumentsOperation inserts the documents of the operation. The
+// documents rejected by the database are reported in the returned error.
+func InsertDocumentsOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := documentsOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		_, errs, err := coll.CreateDocuments(ctx, opts.Documents)
+		if err != nil {
+			return err
+		}
+
+		return documentErrors(o.Collection, 0, errs)
+	}
+}
+
+// UpsertDocumentsOperation inserts the documents of the operation, updating
+// the existing documents with the same key instead. The documents rejected by
+// the database are reported in the returned error.
+func UpsertDocumentsOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := keyedDocumentsOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		if err := opts.validate(); err != nil {
+			return err
+		}
+
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		_, errs, err := coll.CreateDocuments(driver.WithOverwriteMode(ctx, driver.OverwriteModeUpdate), opts.Documents)
+		if err != nil {
+			return err
+		}
+
+		return documentErrors(o.Collection, 0, errs)
+	}
+}
+
+// ReplaceDocumentsOperation replaces the existing documents having the keys of
+// the documents of the operation. The documents rejected by the database, like
+// the missing ones, are reported in the returned error.
+func ReplaceDocumentsOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := keyedDocumentsOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		if err := opts.validate(); err != nil {
+			return err
+		}
+
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		_, errs, err := coll.ReplaceDocuments(ctx, opts.keys(), opts.Documents)
+		if err != nil {
+			return err
+		}
+
+		return documentErrors(o.Collection, 0, errs)
+	}
+}
+
+// RemoveDocumentsOperation removes the documents having the keys of the
+// operation. The documents rejected by the database, like the missing ones,
+// are reported in the returned error.
+func RemoveDocumentsOperation(o *Operation) OperationFn {
+	return func(ctx context.Context, db driver.Database) error {
+		opts := removeDocumentsOpts{}
+		if err := convertToOperationOptions(o.Options, &opts); err != nil {
+			return err
+		}
+
+		coll, err := db.Collection(ctx, o.Collection)
+		if err != nil {
+			return err
+		}
+
+		_, errs, err := coll.RemoveDocuments(ctx, opts.Keys)
+		if err != nil {
+			return err
+		}
+
+		return documentErrors(o.Collection, 0, errs)
+	}
+}
+
 // CreateGraphOperation creates a graph.
 func CreateGraphOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
@@ -222,11 +831,6 @@ func CreateGraphOperation(o *Operation) OperationFn {
 // AddVertexOperation adds a vertex collection to a graph.
 func AddVertexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type addVertexOpts struct {
-			driver.CreateVertexCollectionOptions
-			Collection string `json:"collection"`
-		}
-
 		opts := addVertexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -246,10 +850,6 @@ func AddVertexOperation(o *Operation) OperationFn {
 // RemoveVertexOperation removes a vertex collection from a graph.
 func RemoveVertexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type removeVertexOpts struct {
-			Collection string `json:"collection"`
-		}
-
 		opts := removeVertexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -272,12 +872,6 @@ func RemoveVertexOperation(o *Operation) OperationFn {
 // AddEdgeOperation adds an edge definition to a graph.
 func AddEdgeOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type addEdgeOpts struct {
-			driver.CreateEdgeCollectionOptions
-			Collection  string                   `json:"collection"`
-			Constraints driver.VertexConstraints `json:"constraints"`
-		}
-
 		opts := addEdgeOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -297,10 +891,6 @@ func AddEdgeOperation(o *Operation) OperationFn {
 // RemoveEdgeOperation removes an edge definition from a graph.
 func RemoveEdgeOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type removeEdgeOpts struct {
-			Collection string `json:"collection"`
-		}
-
 		opts := removeEdgeOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -381,11 +971,6 @@ func DeleteViewOperation(o *Operation) OperationFn {
 
 func CreateFulltextIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type fulltextIndexOpts struct {
-			driver.EnsureFullTextIndexOptions
-			Fields []string `json:"fields"`
-		}
-
 		opts := fulltextIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -404,11 +989,6 @@ func CreateFulltextIndexOperation(o *Operation) OperationFn {
 
 func CreateGeoSpatialIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type geoSpatialIndexOpts struct {
-			driver.EnsureGeoIndexOptions
-			Fields []string `json:"fields"`
-		}
-
 		opts := geoSpatialIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -427,11 +1007,6 @@ func CreateGeoSpatialIndexOperation(o *Operation) OperationFn {
 
 func CreateHashIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type hashIndexOpts struct {
-			driver.EnsureHashIndexOptions
-			Fields []string `json:"fields"`
-		}
-
 		opts := hashIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -468,11 +1043,6 @@ func CreateInvertedIndexOperation(o *Operation) OperationFn {
 
 func CreatePersistentIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type persistentIndexOpts struct {
-			driver.EnsurePersistentIndexOptions
-			Fields []string `json:"fields"`
-		}
-
 		opts := persistentIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -491,11 +1061,6 @@ func CreatePersistentIndexOperation(o *Operation) OperationFn {
 
 func CreateSkipListIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type skipListIndexOpts struct {
-			driver.EnsureSkipListIndexOptions
-			Fields []string `json:"fields"`
-		}
-
 		opts := skipListIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -514,12 +1079,6 @@ func CreateSkipListIndexOperation(o *Operation) OperationFn {
 
 func CreateTTLIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type ttlIndexOpts struct {
-			driver.EnsureTTLIndexOptions
-			Field       string `json:"field"`
-			ExpireAfter int    `json:"expireAfter"`
-		}
-
 		opts := ttlIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -538,11 +1097,6 @@ func CreateTTLIndexOperation(o *Operation) OperationFn {
 
 func CreateZKDIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type zkdIndexOpts struct {
-			driver.EnsureZKDIndexOptions
-			Fields []string `json:"fields"`
-		}
-
 		opts := zkdIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -561,10 +1115,6 @@ func CreateZKDIndexOperation(o *Operation) OperationFn {
 
 func DeleteIndexOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-		type deleteIndexOpts struct {
-			Name string `json:"name"`
-		}
-
 		opts := deleteIndexOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -628,12 +1178,6 @@ func CreateAnalyzerOperation(o *Operation) OperationFn {
 
 func DeleteAnalyzerOperation(o *Operation) OperationFn {
 	return func(ctx context.Context, db driver.Database) error {
-
-		type deleteAnalyzerOpts struct {
-			Name  string `json:"name"`
-			Force bool   `json:"force"`
-		}
-
 		opts := deleteAnalyzerOpts{}
 		if err := convertToOperationOptions(o.Options, &opts); err != nil {
 			return err
@@ -647,3 +1191,14 @@ func DeleteAnalyzerOperation(o *Operation) OperationFn {
 		return analyzer.Remove(ctx, opts.Force)
 	}
 }
+
+// goOperation runs the function of the operation of a Go migration.
+func goOperation(o *Operation) OperationFn {
+	if o.fn == nil {
+		return func(_ context.Context, _ driver.Database) error {
+			return fmt.Errorf("%w: %s operation has no function", ErrInvalidOperationKind, o.Kind)
+		}
+	}
+
+	return o.fn
+}
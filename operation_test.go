@@ -2,8 +2,10 @@ package arangom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/arangodb/go-driver"
@@ -38,6 +40,46 @@ func TestOperationKind_UnmarshalYAML(t *testing.T) {
 			value: []byte(`deleteCollection`),
 			want:  OperationKindCollectionDelete,
 		},
+		{
+			name:  "unmarshal renameCollection",
+			value: []byte(`renameCollection`),
+			want:  OperationKindCollectionRename,
+		},
+		{
+			name:  "unmarshal truncateCollection",
+			value: []byte(`truncateCollection`),
+			want:  OperationKindCollectionTruncate,
+		},
+		{
+			name:  "unmarshal backfillAQL",
+			value: []byte(`backfillAQL`),
+			want:  OperationKindAQLBackfill,
+		},
+		{
+			name:  "unmarshal importDocuments",
+			value: []byte(`importDocuments`),
+			want:  OperationKindDocumentsImport,
+		},
+		{
+			name:  "unmarshal insertDocuments",
+			value: []byte(`insertDocuments`),
+			want:  OperationKindDocumentsInsert,
+		},
+		{
+			name:  "unmarshal upsertDocuments",
+			value: []byte(`upsertDocuments`),
+			want:  OperationKindDocumentsUpsert,
+		},
+		{
+			name:  "unmarshal replaceDocuments",
+			value: []byte(`replaceDocuments`),
+			want:  OperationKindDocumentsReplace,
+		},
+		{
+			name:  "unmarshal removeDocuments",
+			value: []byte(`removeDocuments`),
+			want:  OperationKindDocumentsRemove,
+		},
 		{
 			name:  "unmarshal createGraph",
 			value: []byte(`createGraph`),
@@ -201,6 +243,62 @@ func TestOperation_GetOperationFn(t *testing.T) {
 			},
 			want: DeleteCollectionOperation,
 		},
+		{
+			name: "get renameCollection operation",
+			operation: Operation{
+				Kind: OperationKindCollectionRename,
+			},
+			want: RenameCollectionOperation,
+		},
+		{
+			name: "get truncateCollection operation",
+			operation: Operation{
+				Kind: OperationKindCollectionTruncate,
+			},
+			want: TruncateCollectionOperation,
+		},
+		{
+			name: "get backfillAQL operation",
+			operation: Operation{
+				Kind: OperationKindAQLBackfill,
+			},
+			want: BackfillAQLOperation,
+		},
+		{
+			name: "get importDocuments operation",
+			operation: Operation{
+				Kind: OperationKindDocumentsImport,
+			},
+			want: ImportDocumentsOperation,
+		},
+		{
+			name: "get insertDocuments operation",
+			operation: Operation{
+				Kind: OperationKindDocumentsInsert,
+			},
+			want: InsertDocumentsOperation,
+		},
+		{
+			name: "get upsertDocuments operation",
+			operation: Operation{
+				Kind: OperationKindDocumentsUpsert,
+			},
+			want: UpsertDocumentsOperation,
+		},
+		{
+			name: "get replaceDocuments operation",
+			operation: Operation{
+				Kind: OperationKindDocumentsReplace,
+			},
+			want: ReplaceDocumentsOperation,
+		},
+		{
+			name: "get removeDocuments operation",
+			operation: Operation{
+				Kind: OperationKindDocumentsRemove,
+			},
+			want: RemoveDocumentsOperation,
+		},
 		{
 			name: "get createGraph operation",
 			operation: Operation{
@@ -369,6 +467,358 @@ func TestOperation_GetOperationFn(t *testing.T) {
 	}
 }
 
+func TestOperationKind_String(t *testing.T) {
+	tests := []struct {
+		name string
+		kind OperationKind
+		want string
+	}{
+		{
+			name: "known operation kind",
+			kind: OperationKindCollectionCreate,
+			want: "createCollection",
+		},
+		{
+			name: "go operation kind",
+			kind: OperationKindGo,
+			want: "go",
+		},
+		{
+			name: "unknown operation kind",
+			kind: OperationKind(0),
+			want: "OperationKind(0)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.kind.String(); got != tt.want {
+				t.Errorf("OperationKind.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_Inverse(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation *Operation
+		want      *Operation
+		wantErr   bool
+	}{
+		{
+			name: "inverse of createCollection",
+			operation: &Operation{
+				Kind:       OperationKindCollectionCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"waitForSync": true,
+				},
+			},
+			want: &Operation{
+				Kind:       OperationKindCollectionDelete,
+				Collection: "test",
+			},
+		},
+		{
+			name: "inverse of addVertexToGraph",
+			operation: &Operation{
+				Kind:       OperationKindGraphAddVertex,
+				Collection: "graph",
+				Options: map[string]any{
+					"collection": "vertices",
+				},
+			},
+			want: &Operation{
+				Kind:       OperationKindGraphRemoveVertex,
+				Collection: "graph",
+				Options: map[string]any{
+					"collection": "vertices",
+				},
+			},
+		},
+		{
+			name: "inverse of removeVertexFromGraph",
+			operation: &Operation{
+				Kind:       OperationKindGraphRemoveVertex,
+				Collection: "graph",
+				Options: map[string]any{
+					"collection": "vertices",
+				},
+			},
+			want: &Operation{
+				Kind:       OperationKindGraphAddVertex,
+				Collection: "graph",
+				Options: map[string]any{
+					"collection": "vertices",
+				},
+			},
+		},
+		{
+			name: "inverse of createView",
+			operation: &Operation{
+				Kind:       OperationKindViewCreate,
+				Collection: "view",
+			},
+			want: &Operation{
+				Kind:       OperationKindViewDelete,
+				Collection: "view",
+			},
+		},
+		{
+			name: "inverse of named index",
+			operation: &Operation{
+				Kind:       OperationKindPersistentIndexCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"name":   "idx_test",
+					"fields": []string{"test"},
+				},
+			},
+			want: &Operation{
+				Kind:       OperationKindIndexDelete,
+				Collection: "test",
+				Options: map[string]any{
+					"name": "idx_test",
+				},
+			},
+		},
+		{
+			name: "inverse of unnamed index",
+			operation: &Operation{
+				Kind:       OperationKindPersistentIndexCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"fields": []string{"test"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of createAnalyzer",
+			operation: &Operation{
+				Kind: OperationKindAnalyzerCreate,
+				Options: map[string]any{
+					"name": "analyzer",
+					"type": "identity",
+				},
+			},
+			want: &Operation{
+				Kind: OperationKindAnalyzerDelete,
+				Options: map[string]any{
+					"name": "analyzer",
+				},
+			},
+		},
+		{
+			name: "inverse of executeAQL",
+			operation: &Operation{
+				Kind: OperationKindAQLExecute,
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of deleteCollection",
+			operation: &Operation{
+				Kind:       OperationKindCollectionDelete,
+				Collection: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of renameCollection",
+			operation: &Operation{
+				Kind:       OperationKindCollectionRename,
+				Collection: "test",
+				Options: map[string]any{
+					"newName": "renamed",
+				},
+			},
+			want: &Operation{
+				Kind:       OperationKindCollectionRename,
+				Collection: "renamed",
+				Options: map[string]any{
+					"newName": "test",
+				},
+			},
+		},
+		{
+			name: "inverse of renameCollection without new name",
+			operation: &Operation{
+				Kind:       OperationKindCollectionRename,
+				Collection: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of truncateCollection",
+			operation: &Operation{
+				Kind:       OperationKindCollectionTruncate,
+				Collection: "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of insertDocuments",
+			operation: &Operation{
+				Kind:       OperationKindDocumentsInsert,
+				Collection: "test",
+				Options: map[string]any{
+					"documents": []any{
+						map[string]any{"_key": "1", "name": "one"},
+						map[string]any{"_key": "2", "name": "two"},
+					},
+				},
+			},
+			want: &Operation{
+				Kind:       OperationKindDocumentsRemove,
+				Collection: "test",
+				Options:    map[string]any{"keys": []string{"1", "2"}},
+			},
+		},
+		{
+			name: "inverse of insertDocuments without keys",
+			operation: &Operation{
+				Kind:       OperationKindDocumentsInsert,
+				Collection: "test",
+				Options: map[string]any{
+					"documents": []any{map[string]any{"name": "one"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of removeDocuments",
+			operation: &Operation{
+				Kind:       OperationKindDocumentsRemove,
+				Collection: "test",
+				Options:    map[string]any{"keys": []any{"1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inverse of importDocuments",
+			operation: &Operation{
+				Kind:       OperationKindDocumentsImport,
+				Collection: "test",
+				Options:    map[string]any{"file": "test.jsonl"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.operation.Inverse()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Operation.Inverse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Operation.Inverse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_ResolveOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation *Operation
+		want      any
+		wantErr   bool
+	}{
+		{
+			name: "resolve driver options",
+			operation: &Operation{
+				Kind:       OperationKindCollectionCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"waitForSync": true,
+				},
+			},
+			want: &driver.CreateCollectionOptions{
+				WaitForSync: true,
+			},
+		},
+		{
+			name: "resolve extended options",
+			operation: &Operation{
+				Kind:       OperationKindPersistentIndexCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"name":   "idx_test",
+					"fields": []string{"test"},
+				},
+			},
+			want: &persistentIndexOpts{
+				EnsurePersistentIndexOptions: driver.EnsurePersistentIndexOptions{
+					Name: "idx_test",
+				},
+				Fields: []string{"test"},
+			},
+		},
+		{
+			name: "resolve operation without options",
+			operation: &Operation{
+				Kind:       OperationKindCollectionDelete,
+				Collection: "test",
+			},
+			want: nil,
+		},
+		{
+			name: "resolve invalid options",
+			operation: &Operation{
+				Kind:       OperationKindCollectionCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"waitForSync": "yes",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "resolve unknown options",
+			operation: &Operation{
+				Kind:       OperationKindPersistentIndexCreate,
+				Collection: "test",
+				Options: map[string]any{
+					"fileds": []string{"test"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "resolve unknown operation",
+			operation: &Operation{
+				Kind: OperationKind(0),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.operation.ResolveOptions()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Operation.ResolveOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Operation.ResolveOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExecuteAQLOperation(t *testing.T) {
 	type fields struct {
 		operation *Operation
@@ -447,41 +897,197 @@ func TestExecuteAQLOperation(t *testing.T) {
 	}
 }
 
-func TestCreateCollectionOperation(t *testing.T) {
-	type fields struct {
-		operation *Operation
+func TestBackfillAQLOperation(t *testing.T) {
+	const query = "FOR doc IN @@collection FILTER doc._key > @lastKey SORT doc._key LIMIT @batch UPDATE doc WITH { active: @active } IN @@collection RETURN OLD._key"
+
+	keyCursor := func(keys ...string) driver.Cursor {
+		cursor := new(MockArangoCursor)
+		for _, key := range keys {
+			cursor.On("ReadDocument", mock.Anything, mock.Anything).Return(key, driver.DocumentMeta{}, nil).Once()
+		}
+
+		cursor.On("ReadDocument", mock.Anything, mock.Anything).Return(nil, driver.DocumentMeta{}, driver.NoMoreDocumentsError{})
+		cursor.On("Close").Return(nil)
+
+		return cursor
 	}
-	type args struct {
-		ctx context.Context
-		db  driver.Database
+
+	batchVars := func(lastKey string) map[string]any {
+		return map[string]any{"@collection": "users", "active": true, "batch": 2, "lastKey": lastKey}
+	}
+
+	options := map[string]any{
+		"query":     query,
+		"bindVars":  map[string]any{"active": true},
+		"batchSize": 2,
 	}
+
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
+		name            string
+		options         map[string]any
+		checkpoint      string
+		db              func() driver.Database
+		wantCheckpoints []string
+		wantErr         bool
+		wantErrIs       error
 	}{
 		{
-			name: "create collection operation",
-			fields: fields{
-				operation: &Operation{
-					Kind:       OperationKindCollectionCreate,
-					Collection: "test",
-					Options: map[string]any{
-						"waitForSync": true,
-					},
-				},
-			},
-			args: args{
-				ctx: context.Background(),
-				db: func() driver.Database {
-					db := new(MockArangoDB)
-					db.On("CreateCollection", context.Background(), "test", &driver.CreateCollectionOptions{
-						WaitForSync: true,
-					}).Return(new(MockArangoCollection), nil)
+			name:    "backfill aql operation",
+			options: options,
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("Query", mock.Anything, query, batchVars("")).Return(keyCursor("1", "2"), nil).Once()
+				db.On("Query", mock.Anything, query, batchVars("2")).Return(keyCursor("3"), nil).Once()
 
-					return db
-				}(),
+				return db
+			},
+			wantCheckpoints: []string{"2", "3"},
+		},
+		{
+			name:    "backfill aql operation stopping at an empty batch",
+			options: options,
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("Query", mock.Anything, query, batchVars("")).Return(keyCursor("1", "2"), nil).Once()
+				db.On("Query", mock.Anything, query, batchVars("2")).Return(keyCursor(), nil).Once()
+
+				return db
+			},
+			wantCheckpoints: []string{"2"},
+		},
+		{
+			name:       "backfill aql operation resuming from checkpoint",
+			options:    options,
+			checkpoint: "2",
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("Query", mock.Anything, query, batchVars("2")).Return(keyCursor("3"), nil).Once()
+
+				return db
+			},
+			wantCheckpoints: []string{"3"},
+		},
+		{
+			name:    "backfill aql operation with keys ordered by aql",
+			options: options,
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("Query", mock.Anything, query, batchVars("")).Return(keyCursor("a", "b"), nil).Once()
+				db.On("Query", mock.Anything, query, batchVars("b")).Return(keyCursor("C"), nil).Once()
+
+				return db
+			},
+			wantCheckpoints: []string{"b", "C"},
+		},
+		{
+			name:    "backfill aql operation with stalled query",
+			options: options,
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("Query", mock.Anything, query, batchVars("")).Return(keyCursor("1", "2"), nil).Once()
+				db.On("Query", mock.Anything, query, batchVars("2")).Return(keyCursor("1", "2"), nil).Once()
+
+				return db
+			},
+			wantCheckpoints: []string{"2"},
+			wantErr:         true,
+			wantErrIs:       ErrBackfillStalled,
+		},
+		{
+			name:    "backfill aql operation with query error",
+			options: options,
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("Query", mock.Anything, query, batchVars("")).Return(nil, fmt.Errorf("query error")).Once()
+
+				return db
+			},
+			wantErr: true,
+		},
+		{
+			name: "backfill aql operation without last key",
+			options: map[string]any{
+				"query": "FOR doc IN users LIMIT @batch RETURN doc._key",
+			},
+			db: func() driver.Database {
+				return new(MockArangoDB)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var checkpoints []string
+
+			ctx := withOperationProgress(context.Background(), &operationProgress{
+				checkpoint: tt.checkpoint,
+				logger:     &DefaultLogger{Writer: new(strings.Builder), Exiter: func(int) {}},
+				save: func(checkpoint string) error {
+					checkpoints = append(checkpoints, checkpoint)
+					return nil
+				},
+			})
+
+			operation := &Operation{
+				Kind:       OperationKindAQLBackfill,
+				Collection: "users",
+				Options:    tt.options,
+			}
+
+			err := BackfillAQLOperation(operation)(ctx, tt.db())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BackfillAQLOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("BackfillAQLOperation() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if !reflect.DeepEqual(checkpoints, tt.wantCheckpoints) {
+				t.Errorf("BackfillAQLOperation() checkpoints = %v, want %v", checkpoints, tt.wantCheckpoints)
+			}
+		})
+	}
+}
+
+func TestCreateCollectionOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "create collection operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionCreate,
+					Collection: "test",
+					Options: map[string]any{
+						"waitForSync": true,
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("CreateCollection", context.Background(), "test", &driver.CreateCollectionOptions{
+						WaitForSync: true,
+					}).Return(new(MockArangoCollection), nil)
+
+					return db
+				}(),
 			},
 		},
 		{
@@ -713,6 +1319,832 @@ func TestDeleteCollectionOperation(t *testing.T) {
 	}
 }
 
+func TestRenameCollectionOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "rename collection operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionRename,
+					Collection: "test",
+					Options: map[string]any{
+						"newName": "renamed",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("Rename", context.Background(), "renamed").Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "rename collection operation with error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionRename,
+					Collection: "test",
+					Options: map[string]any{
+						"newName": "renamed",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("Rename", context.Background(), "renamed").Return(fmt.Errorf("error"))
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rename collection operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionRename,
+					Collection: "test",
+					Options: map[string]any{
+						"newName": "renamed",
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "rename collection operation with invalid options",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionRename,
+					Collection: "test",
+					Options: map[string]any{
+						"newName": 1,
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := RenameCollectionOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("RenameCollectionOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTruncateCollectionOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "truncate collection operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionTruncate,
+					Collection: "test",
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("Truncate", context.Background()).Return(nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "truncate collection operation with error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionTruncate,
+					Collection: "test",
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("Truncate", context.Background()).Return(fmt.Errorf("error"))
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "truncate collection operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindCollectionTruncate,
+					Collection: "test",
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := TruncateCollectionOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("TruncateCollectionOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportDocumentsOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "import documents operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options: map[string]any{
+						"file":      "test.jsonl",
+						"batchSize": 2,
+					},
+					content: []byte("{\"_key\": \"1\"}\n{\"_key\": \"2\"}\n{\"_key\": \"3\"}\n"),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "1"}, {"_key": "2"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil, nil}, nil)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "3"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "import documents operation updating duplicates",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options: map[string]any{
+						"file":        "test.json",
+						"onDuplicate": "update",
+					},
+					content: []byte(`[{"_key": "1"}]`),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					ctx := driver.WithOverwriteMode(context.Background(), driver.OverwriteModeUpdate)
+
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", ctx, []map[string]any{{"_key": "1"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "import documents operation with rejected documents",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options:    map[string]any{"file": "test.json"},
+					content:    []byte(`[{"_key": "1"}]`),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "1"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{fmt.Errorf("unique constraint violated")}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "import documents operation with error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options:    map[string]any{"file": "test.json"},
+					content:    []byte(`[{"_key": "1"}]`),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "1"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{}, fmt.Errorf("error"))
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "import documents operation with invalid onDuplicate",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options: map[string]any{
+						"file":        "test.json",
+						"onDuplicate": "merge",
+					},
+					content: []byte(`[{"_key": "1"}]`),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+		{
+			name: "import documents operation without loaded file",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options:    map[string]any{"file": "test.json"},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+		{
+			name: "import documents operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsImport,
+					Collection: "test",
+					Options:    map[string]any{"file": "test.json"},
+					content:    []byte(`[{"_key": "1"}]`),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ImportDocumentsOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("ImportDocumentsOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInsertDocumentsOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "insert documents operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsInsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "insert documents operation with rejected documents",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsInsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{fmt.Errorf("unique constraint violated")}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert documents operation with error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsInsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", context.Background(), []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{}, fmt.Errorf("error"))
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert documents operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsInsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := InsertDocumentsOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("InsertDocumentsOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpsertDocumentsOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "upsert documents operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsUpsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", driver.WithOverwriteMode(context.Background(), driver.OverwriteModeUpdate), []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "upsert documents operation with rejected documents",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsUpsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("CreateDocuments", driver.WithOverwriteMode(context.Background(), driver.OverwriteModeUpdate), []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{fmt.Errorf("invalid document")}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "upsert documents operation without keys",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsUpsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+		{
+			name: "upsert documents operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsUpsert,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := UpsertDocumentsOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("UpsertDocumentsOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplaceDocumentsOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "replace documents operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsReplace,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("ReplaceDocuments", context.Background(), []string{"1"}, []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "replace documents operation with missing documents",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsReplace,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("ReplaceDocuments", context.Background(), []string{"1"}, []map[string]any{{"_key": "1", "name": "one"}}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{fmt.Errorf("document not found")}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "replace documents operation without keys",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsReplace,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": 1}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db:  new(MockArangoDB),
+			},
+			wantErr: true,
+		},
+		{
+			name: "replace documents operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsReplace,
+					Collection: "test",
+					Options:    map[string]any{"documents": []any{map[string]any{"_key": "1", "name": "one"}}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := ReplaceDocumentsOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("ReplaceDocumentsOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoveDocumentsOperation(t *testing.T) {
+	type fields struct {
+		operation *Operation
+	}
+	type args struct {
+		ctx context.Context
+		db  driver.Database
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "remove documents operation",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsRemove,
+					Collection: "test",
+					Options:    map[string]any{"keys": []any{"1", "2"}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("RemoveDocuments", context.Background(), []string{"1", "2"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil, nil}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+		},
+		{
+			name: "remove documents operation with missing documents",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsRemove,
+					Collection: "test",
+					Options:    map[string]any{"keys": []any{"1", "2"}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("RemoveDocuments", context.Background(), []string{"1", "2"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil, fmt.Errorf("document not found")}, nil)
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove documents operation with error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsRemove,
+					Collection: "test",
+					Options:    map[string]any{"keys": []any{"1", "2"}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					coll := new(MockArangoCollection)
+					coll.On("RemoveDocuments", context.Background(), []string{"1", "2"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{}, fmt.Errorf("error"))
+
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(coll, nil)
+
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove documents operation with collection open error",
+			fields: fields{
+				operation: &Operation{
+					Kind:       OperationKindDocumentsRemove,
+					Collection: "test",
+					Options:    map[string]any{"keys": []any{"1", "2"}},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("Collection", context.Background(), "test").Return(new(MockArangoCollection), fmt.Errorf("error"))
+					return db
+				}(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := RemoveDocumentsOperation(tt.fields.operation)(tt.args.ctx, tt.args.db); (err != nil) != tt.wantErr {
+				t.Errorf("RemoveDocumentsOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestCreateGraphOperation(t *testing.T) {
 	type fields struct {
 		operation *Operation
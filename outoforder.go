@@ -0,0 +1,106 @@
+package arangom
+
+import (
+	"fmt"
+)
+
+const (
+	// OutOfOrderAllow executes out of order migrations silently.
+	OutOfOrderAllow OutOfOrderPolicy = iota
+	// OutOfOrderWarn executes out of order migrations, logging a warning for
+	// each of them.
+	OutOfOrderWarn
+	// OutOfOrderFail refuses to execute any migration if there are out of
+	// order migrations.
+	OutOfOrderFail
+)
+
+var (
+	// ErrInvalidOutOfOrderPolicy is returned when an unknown out of order
+	// policy is provided.
+	ErrInvalidOutOfOrderPolicy = fmt.Errorf("invalid out of order policy")
+)
+
+// OutOfOrderPolicy is the response of the executor to pending migrations that
+// sort before the latest applied migration.
+type OutOfOrderPolicy int
+
+// String returns the name of the out of order policy.
+func (p OutOfOrderPolicy) String() string {
+	switch p {
+	case OutOfOrderAllow:
+		return "allow"
+	case OutOfOrderWarn:
+		return "warn"
+	case OutOfOrderFail:
+		return "fail"
+	default:
+		return fmt.Sprintf("OutOfOrderPolicy(%d)", int(p))
+	}
+}
+
+// ParseOutOfOrderPolicy returns the out of order policy of the given name.
+func ParseOutOfOrderPolicy(name string) (OutOfOrderPolicy, error) {
+	for _, p := range []OutOfOrderPolicy{OutOfOrderAllow, OutOfOrderWarn, OutOfOrderFail} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrInvalidOutOfOrderPolicy, name)
+}
+
+// WithOutOfOrderPolicy sets the response of the executor to pending migrations
+// that sort before the latest applied migration. By default, the executor
+// fails.
+func WithOutOfOrderPolicy(policy OutOfOrderPolicy) ExecutorOption {
+	return func(e *Executor) error {
+		if policy < OutOfOrderAllow || policy > OutOfOrderFail {
+			return fmt.Errorf("%w: %s", ErrInvalidOutOfOrderPolicy, policy)
+		}
+
+		e.outOfOrderPolicy = policy
+		return nil
+	}
+}
+
+// lastAppliedIndex returns the index of the latest applied migration, or -1 if
+// no migration is applied.
+func lastAppliedIndex(statuses []MigrationStatus) int {
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if !statuses[i].IsPending() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// checkOrder checks the pending migrations that sort before the latest applied
+// migration, responding according to the out of order policy. If the policy
+// is OutOfOrderFail, an error wrapping ErrOutOfOrderMigration is returned.
+func (e *Executor) checkOrder() error {
+	statuses := make([]MigrationStatus, 0, len(e.migrations))
+	for _, migration := range e.migrations {
+		statuses = append(statuses, migration.Status)
+	}
+
+	last := lastAppliedIndex(statuses)
+	for _, migration := range e.migrations[:max(last, 0)] {
+		if !migration.Status.IsPending() {
+			continue
+		}
+
+		err := fmt.Errorf("%w: %s sorts before the applied %s", ErrOutOfOrderMigration, migration.Path, e.migrations[last].Path)
+
+		switch e.outOfOrderPolicy {
+		case OutOfOrderFail:
+			e.logger.Errorf("[%d] migration is out of order; err=%s", migration.ID, err.Error())
+			return err
+		case OutOfOrderWarn:
+			warnf(e.logger, "[%d] migration is out of order; %s", migration.ID, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package arangom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseOutOfOrderPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    OutOfOrderPolicy
+		wantErr bool
+	}{
+		{
+			name: "allow",
+			want: OutOfOrderAllow,
+		},
+		{
+			name: "warn",
+			want: OutOfOrderWarn,
+		},
+		{
+			name: "fail",
+			want: OutOfOrderFail,
+		},
+		{
+			name:    "unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseOutOfOrderPolicy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutOfOrderPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseOutOfOrderPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOutOfOrderPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  OutOfOrderPolicy
+		want    OutOfOrderPolicy
+		wantErr bool
+	}{
+		{
+			name:   "with warn policy",
+			policy: OutOfOrderWarn,
+			want:   OutOfOrderWarn,
+		},
+		{
+			name:    "with unknown policy",
+			policy:  OutOfOrderPolicy(-1),
+			want:    OutOfOrderFail,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{outOfOrderPolicy: OutOfOrderFail}
+			if err := WithOutOfOrderPolicy(tt.policy)(e); (err != nil) != tt.wantErr {
+				t.Errorf("WithOutOfOrderPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if e.outOfOrderPolicy != tt.want {
+				t.Errorf("WithOutOfOrderPolicy() = %v, want %v", e.outOfOrderPolicy, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_outOfOrderPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy OutOfOrderPolicy
+		logger func() Logger
+	}{
+		{
+			name:   "warn about out of order migration",
+			policy: OutOfOrderWarn,
+			logger: func() Logger {
+				logger := new(MockLogger)
+				logger.On("Warnf", "[%d] migration is out of order; %s", []any{123, "out of order migration: 123_create_users.yaml sorts before the applied 124_create_posts.yaml"}).Return().Once()
+				return logger
+			},
+		},
+		{
+			name:   "allow out of order migration",
+			policy: OutOfOrderAllow,
+			logger: func() Logger {
+				return new(MockLogger)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := mock.Anything
+
+			coll := new(MockArangoCollection)
+			mockLock(coll)
+			coll.On("DocumentExists", ctx, "124").Return(true, nil)
+			coll.On("ReadDocument", ctx, "124", mock.Anything).Return(&MigrationItem{
+				Key:    "124",
+				Status: MigrationStatusDone,
+			}, driver.DocumentMeta{}, nil)
+			coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+			mockLegacyRecords(coll)
+			coll.On("CreateDocument", ctx, mock.AnythingOfType("*arangom.MigrationItem")).Return(driver.DocumentMeta{}, nil)
+
+			db := new(MockArangoDB)
+			db.On("Collection", ctx, "test").Return(coll, nil)
+
+			logger := tt.logger().(*MockLogger)
+			logger.On("Infof", mock.Anything, mock.Anything).Return()
+			logger.On("Info", []any{"all migrations executed successfully"}).Return()
+
+			e := &Executor{
+				db:         db,
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID:   123,
+						Path: "123_create_users.yaml",
+					},
+					{
+						ID:   124,
+						Path: "124_create_posts.yaml",
+					},
+				},
+				logger:           logger,
+				lockTTL:          DefaultLockTTL,
+				outOfOrderPolicy: tt.policy,
+			}
+
+			if err := e.Execute(context.Background()); err != nil {
+				t.Fatalf("Executor.Execute() error = %v", err)
+			}
+
+			logger.AssertExpectations(t)
+			logger.AssertCalled(t, "Infof", "[%d] migration executed successfully", []any{123})
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package arangom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PlanActionRun is the action of a migration that would be executed.
+	PlanActionRun PlanAction = iota
+	// PlanActionSkip is the action of a migration that is already executed.
+	PlanActionSkip
+	// PlanActionBlock is the action of a migration that failed or follows a
+	// failed migration, therefore it would stop the execution.
+	PlanActionBlock
+)
+
+// PlanAction is the action the executor would take for a migration.
+type PlanAction int
+
+// String returns the name of the plan action.
+func (a PlanAction) String() string {
+	switch a {
+	case PlanActionRun:
+		return "run"
+	case PlanActionSkip:
+		return "skip"
+	case PlanActionBlock:
+		return "block"
+	default:
+		return fmt.Sprintf("PlanAction(%d)", int(a))
+	}
+}
+
+// PlannedOperation is an operation that would be executed, along with its
+// options resolved the same way as during the execution.
+type PlannedOperation struct {
+	Kind       OperationKind
+	Collection string
+	Options    any
+}
+
+// PlannedMigration is a migration and the action the executor would take for
+// it. The operations are resolved only for migrations that would be executed.
+type PlannedMigration struct {
+	Migration  *Migration
+	Action     PlanAction
+	Operations []*PlannedOperation
+}
+
+// Plan describes what the executor would do when executing the migrations.
+type Plan struct {
+	Migrations []*PlannedMigration
+}
+
+// Plan reports what Execute would do without changing the database, including
+// the migration collection. If the migration collection does not exist, every
+// migration is considered missing.
+func (e *Executor) Plan(ctx context.Context) (*Plan, error) {
+	e.logger.Infof("connecting to the migration collection \"%s\"", e.collection)
+	collExists, err := e.db.CollectionExists(ctx, e.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	var coll driver.Collection
+	if collExists {
+		if coll, err = e.db.Collection(ctx, e.collection); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &Plan{
+		Migrations: make([]*PlannedMigration, 0, len(e.migrations)),
+	}
+
+	for _, migration := range e.migrations {
+		migration.Status = MigrationStatusMissing
+
+		if coll != nil {
+			if err := e.fetchStatus(ctx, coll, migration); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := e.checkOrder(); err != nil {
+		return nil, err
+	}
+
+	blocked := false
+	for _, migration := range e.migrations {
+		planned := &PlannedMigration{
+			Migration: migration,
+		}
+
+		switch {
+		case migration.Status.isRollingBack():
+			e.logger.Errorf("[%d] migration is partially rolled back, roll it back again", migration.ID)
+			planned.Action = PlanActionBlock
+			blocked = true
+		case migration.Status == MigrationStatusRunning:
+			e.logger.Errorf("[%d] migration was interrupted", migration.ID)
+			planned.Action = PlanActionBlock
+			blocked = true
+		case migration.Status == MigrationStatusFailed:
+			e.logger.Errorf("[%d] migration failed", migration.ID)
+			planned.Action = PlanActionBlock
+			blocked = true
+		case !migration.Status.IsPending():
+			planned.Action = PlanActionSkip
+
+			if err := migration.VerifyChecksum(); err != nil {
+				if !errors.Is(err, ErrChecksumMismatch) {
+					return nil, err
+				}
+
+				if !e.allowChecksumChange {
+					e.logger.Errorf("[%d] migration changed since it was executed; err=%s", migration.ID, err.Error())
+					planned.Action = PlanActionBlock
+					blocked = true
+					break
+				}
+
+				e.logger.Infof("[%d] migration changed since it was executed, the recorded checksum would be updated; %s", migration.ID, err.Error())
+			}
+
+			e.logger.Infof("[%d] migration already executed", migration.ID)
+		case blocked:
+			e.logger.Infof("[%d] migration blocked by a failed migration", migration.ID)
+			planned.Action = PlanActionBlock
+		default:
+			e.logger.Infof("[%d] migration would be executed", migration.ID)
+			planned.Action = PlanActionRun
+
+			if planned.Operations, err = e.planOperations(migration); err != nil {
+				return nil, err
+			}
+		}
+
+		plan.Migrations = append(plan.Migrations, planned)
+	}
+
+	return plan, nil
+}
+
+// planOperations resolves the operations of the migration and logs them.
+func (e *Executor) planOperations(migration *Migration) ([]*PlannedOperation, error) {
+	operations := make([]*PlannedOperation, 0, len(migration.Operations))
+
+	for i, operation := range migration.Operations {
+		opts, err := operation.ResolveOptions()
+		if err != nil {
+			err = errors.Wrapf(err, "operation #%d %s", i+1, operation.Kind)
+			e.logger.Errorf("[%d] invalid operation; err=%s", migration.ID, err.Error())
+			return nil, err
+		}
+
+		b, err := json.Marshal(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		e.logger.Infof("[%d] operation #%d %s on \"%s\" with options %s", migration.ID, i+1, operation.Kind, operation.Collection, string(b))
+
+		operations = append(operations, &PlannedOperation{
+			Kind:       operation.Kind,
+			Collection: operation.Collection,
+			Options:    opts,
+		})
+	}
+
+	return operations, nil
+}
@@ -0,0 +1,312 @@
+package arangom
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPlanAction_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		action PlanAction
+		want   string
+	}{
+		{
+			name:   "run",
+			action: PlanActionRun,
+			want:   "run",
+		},
+		{
+			name:   "skip",
+			action: PlanActionSkip,
+			want:   "skip",
+		},
+		{
+			name:   "block",
+			action: PlanActionBlock,
+			want:   "block",
+		},
+		{
+			name:   "unknown",
+			action: PlanAction(-1),
+			want:   "PlanAction(-1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.action.String(); got != tt.want {
+				t.Errorf("PlanAction.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_Plan(t *testing.T) {
+	type fields struct {
+		db         driver.Database
+		collection string
+		migrations []*Migration
+		logger     Logger
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []PlanAction
+		wantOps [][]*PlannedOperation
+		wantErr bool
+	}{
+		{
+			name: "plan pending and applied migrations",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := context.Background()
+					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+					coll := new(MockArangoCollection)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:      "123",
+						Checksum: checksum,
+						Status:   MigrationStatusDone,
+					}, driver.DocumentMeta{}, nil)
+
+					db := new(MockArangoDB)
+					db.On("CollectionExists", ctx, "test").Return(true, nil)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+					{
+						ID: 124,
+						Operations: []*Operation{
+							{
+								Kind:       OperationKindCollectionCreate,
+								Collection: "users",
+								Options: map[string]any{
+									"waitForSync": true,
+								},
+							},
+						},
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+					logger.On("Infof", "[%d] migration already executed", []any{123}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{124}).Return()
+					logger.On("Infof", "[%d] migration would be executed", []any{124}).Return()
+					logger.On("Infof", "[%d] operation #%d %s on \"%s\" with options %s", mock.Anything).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: []PlanAction{PlanActionSkip, PlanActionRun},
+			wantOps: [][]*PlannedOperation{
+				nil,
+				{
+					{
+						Kind:       OperationKindCollectionCreate,
+						Collection: "users",
+						Options: &driver.CreateCollectionOptions{
+							WaitForSync: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "plan without migration collection",
+			fields: fields{
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("CollectionExists", context.Background(), "test").Return(false, nil)
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] migration would be executed", []any{123}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    []PlanAction{PlanActionRun},
+			wantOps: [][]*PlannedOperation{{}},
+		},
+		{
+			name: "plan with failed migration",
+			fields: fields{
+				db: func() driver.Database {
+					ctx := context.Background()
+					checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+
+					coll := new(MockArangoCollection)
+					coll.On("DocumentExists", ctx, "123").Return(true, nil)
+					coll.On("DocumentExists", ctx, mock.Anything).Return(false, nil)
+					coll.On("ReadDocument", ctx, "123", mock.Anything).Return(&MigrationItem{
+						Key:      "123",
+						Checksum: checksum,
+						Status:   MigrationStatusFailed,
+					}, driver.DocumentMeta{}, nil)
+
+					db := new(MockArangoDB)
+					db.On("CollectionExists", ctx, "test").Return(true, nil)
+					db.On("Collection", ctx, "test").Return(coll, nil)
+
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+					{
+						ID: 124,
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{123}).Return()
+					logger.On("Errorf", "[%d] migration failed", []any{123}).Return()
+					logger.On("Infof", "[%d] fetching migration status", []any{124}).Return()
+					logger.On("Infof", "[%d] migration blocked by a failed migration", []any{124}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    []PlanAction{PlanActionBlock, PlanActionBlock},
+			wantOps: [][]*PlannedOperation{nil, nil},
+		},
+		{
+			name: "plan with invalid operation options",
+			fields: fields{
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("CollectionExists", context.Background(), "test").Return(false, nil)
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+						Operations: []*Operation{
+							{
+								Kind:       OperationKindCollectionCreate,
+								Collection: "users",
+								Options: map[string]any{
+									"waitForSync": "yes",
+								},
+							},
+						},
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					logger.On("Infof", "[%d] migration would be executed", []any{123}).Return()
+					logger.On("Errorf", "[%d] invalid operation; err=%s", mock.Anything).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "plan with collection check error",
+			fields: fields{
+				db: func() driver.Database {
+					db := new(MockArangoDB)
+					db.On("CollectionExists", context.Background(), "test").Return(false, fmt.Errorf("error"))
+					return db
+				}(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID: 123,
+					},
+				},
+				logger: func() Logger {
+					logger := new(MockLogger)
+					logger.On("Infof", "connecting to the migration collection \"%s\"", []any{"test"}).Return()
+					return logger
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.fields.db,
+				collection: tt.fields.collection,
+				migrations: tt.fields.migrations,
+				logger:     tt.fields.logger,
+			}
+
+			got, err := e.Plan(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Executor.Plan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			actions := make([]PlanAction, 0, len(got.Migrations))
+			operations := make([][]*PlannedOperation, 0, len(got.Migrations))
+			for _, planned := range got.Migrations {
+				actions = append(actions, planned.Action)
+				operations = append(operations, planned.Operations)
+			}
+
+			if !reflect.DeepEqual(actions, tt.want) {
+				t.Errorf("Executor.Plan() actions = %v, want %v", actions, tt.want)
+			}
+
+			if !reflect.DeepEqual(operations, tt.wantOps) {
+				t.Errorf("Executor.Plan() operations = %v, want %v", operations, tt.wantOps)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package arangom
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// jsonSchemaDraft is the JSON Schema draft the schema of the migration files
+// follows.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema returns the JSON Schema of the YAML and JSON migration files. The
+// schema of the options of every operation kind is derived from the options
+// the operation uses, therefore it follows the supported operation kinds.
+func JSONSchema() ([]byte, error) {
+	definitions := make(map[string]any)
+
+	operations := map[string]any{
+		"type":  "array",
+		"items": map[string]any{"$ref": "#/definitions/operation"},
+	}
+
+	schema := map[string]any{
+		"$schema":              jsonSchemaDraft,
+		"title":                "arangom migration",
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"id":          map[string]any{"type": "integer"},
+			"operations":  operations,
+			"down":        operations,
+			"transaction": map[string]any{"$ref": "#/definitions/transaction"},
+		},
+		"definitions": definitions,
+	}
+
+	kinds := slices.Sorted(maps.Keys(operationMap))
+	conditions := make([]any, 0, len(kinds))
+
+	for _, name := range kinds {
+		kind := operationMap[name]
+
+		required := []string{"kind"}
+		if !slices.Contains(collectionlessKinds, kind) {
+			required = append(required, "collection")
+		}
+
+		options := map[string]any{"type": "object", "maxProperties": 0}
+		if newOpts, ok := operationOptionsMap[kind]; ok {
+			options = structSchema(reflect.TypeOf(newOpts()).Elem(), definitions)
+		}
+
+		if requiredOpts, ok := requiredOptions[kind]; ok {
+			options["required"] = requiredOpts
+			required = append(required, "options")
+		}
+
+		definitions[name+"Options"] = options
+
+		conditions = append(conditions, map[string]any{
+			"if": map[string]any{
+				"properties": map[string]any{"kind": map[string]any{"const": name}},
+			},
+			"then": map[string]any{
+				"required": required,
+				"properties": map[string]any{
+					"options": map[string]any{"$ref": "#/definitions/" + name + "Options"},
+				},
+			},
+		})
+	}
+
+	collections := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	definitions["transaction"] = map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"read":      collections,
+			"write":     collections,
+			"exclusive": collections,
+		},
+	}
+
+	definitions["operation"] = map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"kind"},
+		"properties": map[string]any{
+			"kind":       map[string]any{"enum": kinds},
+			"collection": map[string]any{"type": "string"},
+			"options":    map[string]any{"type": "object"},
+		},
+		"allOf": conditions,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the JSON Schema of the type as encoded by encoding/json.
+// The named structs are added to the definitions and referenced.
+func typeSchema(t reflect.Type, definitions map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeFor[time.Duration]() {
+		return map[string]any{"type": "integer"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string"}
+		}
+
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), definitions)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), definitions)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, definitions)
+		}
+
+		name := t.Name()
+		if _, ok := definitions[name]; !ok {
+			// The definition is reserved before it is built to terminate the
+			// recursion of recursive types.
+			definitions[name] = nil
+			definitions[name] = structSchema(t, definitions)
+		}
+
+		return map[string]any{"$ref": "#/definitions/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the JSON Schema of the struct type, rejecting the
+// properties unknown to it.
+func structSchema(t reflect.Type, definitions map[string]any) map[string]any {
+	properties := make(map[string]any)
+	for name, field := range jsonFields(t) {
+		properties[name] = typeSchema(field, definitions)
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           properties,
+	}
+}
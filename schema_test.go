@@ -0,0 +1,149 @@
+package arangom
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestJSONSchema(t *testing.T) {
+	b, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	var schema struct {
+		Definitions map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"definitions"`
+	}
+
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("JSONSchema() returned invalid JSON: %v", err)
+	}
+
+	kinds := schema.Definitions["operation"].Properties["kind"].Enum
+	if len(kinds) != len(operationMap) {
+		t.Errorf("JSONSchema() has %d operation kinds, want %d", len(kinds), len(operationMap))
+	}
+
+	for _, kind := range kinds {
+		if _, ok := operationMap[kind]; !ok {
+			t.Errorf("JSONSchema() has unknown operation kind %s", kind)
+		}
+
+		if _, ok := schema.Definitions[kind+"Options"]; !ok {
+			t.Errorf("JSONSchema() has no options of %s", kind)
+		}
+	}
+
+	persistentIndex := schema.Definitions["createPersistentIndexOptions"]
+	for _, name := range []string{"fields", "name", "unique", "sparse"} {
+		if _, ok := persistentIndex.Properties[name]; !ok {
+			t.Errorf("JSONSchema() has no %s option of createPersistentIndex", name)
+		}
+	}
+
+	if !slices.Equal(persistentIndex.Required, []string{"fields"}) {
+		t.Errorf("JSONSchema() requires %v options of createPersistentIndex, want [fields]", persistentIndex.Required)
+	}
+}
+
+func TestJSONSchema_upToDate(t *testing.T) {
+	want, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	got, err := os.ReadFile("migration.schema.json")
+	if err != nil {
+		t.Fatalf("failed to read the schema: %v", err)
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(got), want) {
+		t.Errorf("migration.schema.json is out of date, run make schema")
+	}
+}
+
+func TestTypeSchema(t *testing.T) {
+	type node struct {
+		Name     string  `json:"name"`
+		Children []*node `json:"children"`
+		Ignored  string  `json:"-"`
+	}
+
+	tests := []struct {
+		name            string
+		typ             reflect.Type
+		want            map[string]any
+		wantDefinitions []string
+	}{
+		{
+			name: "boolean",
+			typ:  reflect.TypeFor[*bool](),
+			want: map[string]any{"type": "boolean"},
+		},
+		{
+			name: "integer",
+			typ:  reflect.TypeFor[uint16](),
+			want: map[string]any{"type": "integer"},
+		},
+		{
+			name: "duration",
+			typ:  reflect.TypeFor[time.Duration](),
+			want: map[string]any{"type": "integer"},
+		},
+		{
+			name: "number",
+			typ:  reflect.TypeFor[float64](),
+			want: map[string]any{"type": "number"},
+		},
+		{
+			name: "list",
+			typ:  reflect.TypeFor[[]string](),
+			want: map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		{
+			name: "map",
+			typ:  reflect.TypeFor[map[string]any](),
+			want: map[string]any{"type": "object", "additionalProperties": map[string]any{}},
+		},
+		{
+			name:            "recursive struct",
+			typ:             reflect.TypeFor[node](),
+			want:            map[string]any{"$ref": "#/definitions/node"},
+			wantDefinitions: []string{"node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			definitions := make(map[string]any)
+
+			if got := typeSchema(tt.typ, definitions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("typeSchema() = %v, want %v", got, tt.want)
+			}
+
+			for _, name := range tt.wantDefinitions {
+				definition, ok := definitions[name].(map[string]any)
+				if !ok {
+					t.Fatalf("typeSchema() did not define %s", name)
+				}
+
+				properties := definition["properties"].(map[string]any)
+				if _, ok := properties["Ignored"]; ok || len(properties) != 2 {
+					t.Errorf("typeSchema() defined %s with properties %v", name, properties)
+				}
+			}
+		})
+	}
+}
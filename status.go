@@ -0,0 +1,174 @@
+package arangom
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/arangodb/go-driver"
+)
+
+// migrationItemsQuery returns every migration item of the migration collection.
+const migrationItemsQuery = `FOR doc IN @@collection FILTER doc._key != @lockKey SORT doc._key RETURN doc`
+
+// MigrationState is the state of a migration file or of a migration item that
+// no migration file matches.
+type MigrationState struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	Path           string          `json:"path,omitempty"`
+	Status         MigrationStatus `json:"status"`
+	AppliedAt      *time.Time      `json:"appliedAt,omitempty"`
+	Checksum       string          `json:"checksum,omitempty"`
+	StoredChecksum string          `json:"storedChecksum,omitempty"`
+	Orphaned       bool            `json:"orphaned"`
+	// OutOfOrder is true if the migration is pending, but it sorts before the
+	// latest applied migration.
+	OutOfOrder bool `json:"outOfOrder"`
+}
+
+// Changed returns true if the migration changed since it was executed.
+func (s *MigrationState) Changed() bool {
+	return !s.Orphaned && s.StoredChecksum != "" && s.StoredChecksum != s.Checksum && !s.Status.IsPending()
+}
+
+// Status returns the state of every migration, followed by the migration items
+// of the migration collection that no migration matches. The checksum of a
+// migration is its current checksum, while the stored checksum is the one
+// recorded when it was last executed. Pending migrations sorting before the
+// latest applied migration are marked as out of order. Status does not change
+// the database.
+func (e *Executor) Status(ctx context.Context) ([]*MigrationState, error) {
+	items, err := e.fetchMigrationItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*MigrationState, 0, len(e.migrations)+len(items))
+	for _, migration := range e.migrations {
+		checksum, err := migration.Checksum()
+		if err != nil {
+			return nil, err
+		}
+
+		state := &MigrationState{
+			ID:       migration.ID,
+			Name:     migration.Name(),
+			Path:     migration.Path,
+			Status:   MigrationStatusMissing,
+			Checksum: checksum,
+		}
+
+		for _, key := range []string{migration.Key(), checksum} {
+			item, ok := items[key]
+			if !ok {
+				continue
+			}
+
+			delete(items, key)
+
+			// The legacy items are keyed by the checksum itself.
+			if item.Checksum == "" && key == checksum {
+				item.Checksum = checksum
+			}
+
+			state.Status = item.Status
+			state.AppliedAt = appliedAt(item)
+			state.StoredChecksum = item.Checksum
+			break
+		}
+
+		// The legacy items of migrations changed since they were executed are
+		// matched by name, as they are keyed by the former checksum.
+		for key, item := range items {
+			if state.StoredChecksum != "" || item.Checksum != "" || item.Name == "" || item.Name != state.Name {
+				continue
+			}
+
+			delete(items, key)
+
+			state.Status = item.Status
+			state.AppliedAt = appliedAt(item)
+			state.StoredChecksum = key
+		}
+
+		states = append(states, state)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(states))
+	for _, state := range states {
+		statuses = append(statuses, state.Status)
+	}
+
+	for _, state := range states[:max(lastAppliedIndex(statuses), 0)] {
+		state.OutOfOrder = state.Status.IsPending()
+	}
+
+	orphaned := make([]*MigrationState, 0, len(items))
+	for _, item := range items {
+		orphaned = append(orphaned, &MigrationState{
+			ID:             item.MigrationID,
+			Name:           item.Name,
+			Status:         item.Status,
+			AppliedAt:      appliedAt(item),
+			StoredChecksum: item.Checksum,
+			Orphaned:       true,
+		})
+	}
+
+	slices.SortFunc(orphaned, func(a, b *MigrationState) int {
+		return cmp.Or(cmp.Compare(a.ID, b.ID), cmp.Compare(a.Name, b.Name))
+	})
+
+	return append(states, orphaned...), nil
+}
+
+// appliedAt returns the time the migration item was applied, or nil if it was
+// never applied.
+func appliedAt(item *MigrationItem) *time.Time {
+	if item.AppliedAt.IsZero() {
+		return nil
+	}
+
+	return &item.AppliedAt
+}
+
+// fetchMigrationItems returns the migration items of the migration collection
+// by their key. If the migration collection does not exist, no items are
+// returned.
+func (e *Executor) fetchMigrationItems(ctx context.Context) (map[string]*MigrationItem, error) {
+	items := make(map[string]*MigrationItem)
+
+	exists, err := e.db.CollectionExists(ctx, e.collection)
+	if err != nil || !exists {
+		return items, err
+	}
+
+	cursor, err := e.db.Query(ctx, migrationItemsQuery, map[string]any{
+		"@collection": e.collection,
+		"lockKey":     LockKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.HasMore() {
+		item := new(MigrationItem)
+		if _, err := cursor.ReadDocument(ctx, item); err != nil {
+			if driver.IsNoMoreDocuments(err) {
+				break
+			}
+
+			return nil, err
+		}
+
+		items[item.Key] = item
+	}
+
+	return items, nil
+}
@@ -0,0 +1,315 @@
+package arangom
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockCursor returns a cursor returning the given documents in order.
+func mockCursor(docs ...any) *MockArangoCursor {
+	cursor := new(MockArangoCursor)
+	for _, doc := range docs {
+		cursor.On("HasMore").Return(true).Once()
+		cursor.On("ReadDocument", mock.Anything, mock.Anything).Return(doc, driver.DocumentMeta{}, nil).Once()
+	}
+	cursor.On("HasMore").Return(false)
+	cursor.On("Close").Return(nil)
+
+	return cursor
+}
+
+func TestExecutor_Status(t *testing.T) {
+	appliedAt := time.Date(2023, 2, 28, 6, 47, 43, 0, time.UTC)
+	checksum := "a4af0f02f3ed717e72f74da096cf4a3e36af3bdae4515fdd664f316163341ef2"
+	pendingChecksum := "86382b50ded6794f123a816744e6f4564e3c13a0a729a04068337e2e95a236e7"
+
+	tests := []struct {
+		name    string
+		db      func() driver.Database
+		want    []*MigrationState
+		wantErr bool
+	}{
+		{
+			name: "status of applied, pending and orphaned migrations",
+			db: func() driver.Database {
+				ctx := context.Background()
+
+				cursor := mockCursor(
+					&MigrationItem{
+						Key:         "123",
+						MigrationID: 123,
+						Name:        "123_create_users",
+						Checksum:    checksum,
+						Status:      MigrationStatusDone,
+						AppliedAt:   appliedAt,
+					},
+					&MigrationItem{
+						Key:         "99",
+						MigrationID: 99,
+						Name:        "99_removed",
+						Checksum:    "old",
+						Status:      MigrationStatusFailed,
+						AppliedAt:   appliedAt,
+					},
+				)
+
+				db := new(MockArangoDB)
+				db.On("CollectionExists", ctx, "test").Return(true, nil)
+				db.On("Query", ctx, migrationItemsQuery, map[string]any{
+					"@collection": "test",
+					"lockKey":     LockKey,
+				}).Return(cursor, nil)
+
+				return db
+			},
+			want: []*MigrationState{
+				{
+					ID:             123,
+					Name:           "123_create_users",
+					Path:           "123_create_users.yaml",
+					Status:         MigrationStatusDone,
+					AppliedAt:      &appliedAt,
+					Checksum:       checksum,
+					StoredChecksum: checksum,
+				},
+				{
+					ID:       124,
+					Name:     "124_create_posts",
+					Path:     "124_create_posts.yaml",
+					Status:   MigrationStatusMissing,
+					Checksum: pendingChecksum,
+				},
+				{
+					ID:             99,
+					Name:           "99_removed",
+					Status:         MigrationStatusFailed,
+					AppliedAt:      &appliedAt,
+					StoredChecksum: "old",
+					Orphaned:       true,
+				},
+			},
+		},
+		{
+			name: "status of migration recorded by checksum",
+			db: func() driver.Database {
+				ctx := context.Background()
+
+				cursor := mockCursor(&MigrationItem{
+					Key:       checksum,
+					Name:      "123_create_users",
+					Status:    MigrationStatusDone,
+					AppliedAt: appliedAt,
+				})
+
+				db := new(MockArangoDB)
+				db.On("CollectionExists", ctx, "test").Return(true, nil)
+				db.On("Query", ctx, migrationItemsQuery, mock.Anything).Return(cursor, nil)
+
+				return db
+			},
+			want: []*MigrationState{
+				{
+					ID:             123,
+					Name:           "123_create_users",
+					Path:           "123_create_users.yaml",
+					Status:         MigrationStatusDone,
+					AppliedAt:      &appliedAt,
+					Checksum:       checksum,
+					StoredChecksum: checksum,
+				},
+				{
+					ID:       124,
+					Name:     "124_create_posts",
+					Path:     "124_create_posts.yaml",
+					Status:   MigrationStatusMissing,
+					Checksum: pendingChecksum,
+				},
+			},
+		},
+		{
+			name: "status of changed migration recorded by an earlier version",
+			db: func() driver.Database {
+				ctx := context.Background()
+
+				cursor := mockCursor(&MigrationItem{
+					Key:       "old",
+					Name:      "123_create_users",
+					Status:    MigrationStatusDone,
+					AppliedAt: appliedAt,
+				})
+
+				db := new(MockArangoDB)
+				db.On("CollectionExists", ctx, "test").Return(true, nil)
+				db.On("Query", ctx, migrationItemsQuery, mock.Anything).Return(cursor, nil)
+
+				return db
+			},
+			want: []*MigrationState{
+				{
+					ID:             123,
+					Name:           "123_create_users",
+					Path:           "123_create_users.yaml",
+					Status:         MigrationStatusDone,
+					AppliedAt:      &appliedAt,
+					Checksum:       checksum,
+					StoredChecksum: "old",
+				},
+				{
+					ID:       124,
+					Name:     "124_create_posts",
+					Path:     "124_create_posts.yaml",
+					Status:   MigrationStatusMissing,
+					Checksum: pendingChecksum,
+				},
+			},
+		},
+		{
+			name: "status without migration collection",
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("CollectionExists", context.Background(), "test").Return(false, nil)
+				return db
+			},
+			want: []*MigrationState{
+				{
+					ID:       123,
+					Name:     "123_create_users",
+					Path:     "123_create_users.yaml",
+					Status:   MigrationStatusMissing,
+					Checksum: checksum,
+				},
+				{
+					ID:       124,
+					Name:     "124_create_posts",
+					Path:     "124_create_posts.yaml",
+					Status:   MigrationStatusMissing,
+					Checksum: pendingChecksum,
+				},
+			},
+		},
+		{
+			name: "status with query error",
+			db: func() driver.Database {
+				ctx := context.Background()
+
+				db := new(MockArangoDB)
+				db.On("CollectionExists", ctx, "test").Return(true, nil)
+				db.On("Query", ctx, migrationItemsQuery, mock.Anything).Return(nil, driver.ArangoError{HasError: true, Code: 500})
+
+				return db
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Executor{
+				db:         tt.db(),
+				collection: "test",
+				migrations: []*Migration{
+					{
+						ID:   123,
+						Path: "123_create_users.yaml",
+					},
+					{
+						ID:   124,
+						Path: "124_create_posts.yaml",
+					},
+				},
+				logger: new(MockLogger),
+			}
+
+			got, err := e.Status(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Executor.Status() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Executor.Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_Status_outOfOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	cursor := mockCursor(&MigrationItem{
+		Key:         "124",
+		MigrationID: 124,
+		Status:      MigrationStatusDone,
+	})
+
+	db := new(MockArangoDB)
+	db.On("CollectionExists", ctx, "test").Return(true, nil)
+	db.On("Query", ctx, migrationItemsQuery, mock.Anything).Return(cursor, nil)
+
+	e := &Executor{
+		db:         db,
+		collection: "test",
+		migrations: []*Migration{
+			{ID: 123},
+			{ID: 124},
+			{ID: 125},
+		},
+		logger: new(MockLogger),
+	}
+
+	got, err := e.Status(ctx)
+	if err != nil {
+		t.Fatalf("Executor.Status() error = %v", err)
+	}
+
+	want := []bool{true, false, false}
+	for i, state := range got {
+		if state.OutOfOrder != want[i] {
+			t.Errorf("Executor.Status()[%d].OutOfOrder = %v, want %v", i, state.OutOfOrder, want[i])
+		}
+	}
+}
+
+func TestMigrationState_Changed(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *MigrationState
+		want  bool
+	}{
+		{
+			name:  "unchanged migration",
+			state: &MigrationState{Status: MigrationStatusDone, Checksum: "a", StoredChecksum: "a"},
+		},
+		{
+			name:  "changed migration",
+			state: &MigrationState{Status: MigrationStatusDone, Checksum: "b", StoredChecksum: "a"},
+			want:  true,
+		},
+		{
+			name:  "changed rolled back migration",
+			state: &MigrationState{Status: MigrationStatusRolledBack, Checksum: "b", StoredChecksum: "a"},
+		},
+		{
+			name:  "orphaned migration",
+			state: &MigrationState{Status: MigrationStatusDone, StoredChecksum: "a", Orphaned: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.state.Changed(); got != tt.want {
+				t.Errorf("MigrationState.Changed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
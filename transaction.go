@@ -0,0 +1,89 @@
+package arangom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/arangodb/go-driver"
+)
+
+var (
+	// ErrInvalidTransaction is returned when a migration running in a
+	// transaction has operations that cannot run in a stream transaction.
+	ErrInvalidTransaction = fmt.Errorf("invalid transaction")
+
+	// transactionalKinds are the operation kinds that can run in a stream
+	// transaction. The other operations change the schema of the database or
+	// manage their own batches, which stream transactions do not support.
+	transactionalKinds = []OperationKind{
+		OperationKindAQLExecute,
+		OperationKindDocumentsImport,
+		OperationKindDocumentsInsert,
+		OperationKindDocumentsUpsert,
+		OperationKindDocumentsReplace,
+		OperationKindDocumentsRemove,
+	}
+)
+
+// MigrationTransaction declares the collections of the stream transaction the
+// operations of a migration run in. The collections are locked for reading,
+// writing or exclusively when the transaction begins.
+type MigrationTransaction struct {
+	Read      []string `yaml:"read"`
+	Write     []string `yaml:"write"`
+	Exclusive []string `yaml:"exclusive"`
+}
+
+// run calls fn with a context bound to a new stream transaction. The
+// transaction is committed if fn succeeds, and aborted otherwise.
+func (t *MigrationTransaction) run(ctx context.Context, db driver.Database, fn func(ctx context.Context) error) error {
+	collections := driver.TransactionCollections{
+		Read:      t.Read,
+		Write:     t.Write,
+		Exclusive: t.Exclusive,
+	}
+
+	tid, err := db.BeginTransaction(ctx, collections, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(driver.WithTransactionID(ctx, tid)); err != nil {
+		if abortErr := db.AbortTransaction(ctx, tid, nil); abortErr != nil {
+			return errors.Join(err, fmt.Errorf("abort transaction %s: %w", tid, abortErr))
+		}
+
+		return err
+	}
+
+	if err := db.CommitTransaction(ctx, tid, nil); err != nil {
+		return fmt.Errorf("commit transaction %s: %w", tid, err)
+	}
+
+	return nil
+}
+
+// checkTransaction returns an error wrapping ErrInvalidTransaction for every
+// operation of the migration that cannot run in its transaction. Migrations
+// without transaction are not checked.
+func (m *Migration) checkTransaction() error {
+	if m.Transaction == nil {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	check := func(label string, operations []*Operation) {
+		for i, operation := range operations {
+			if !slices.Contains(transactionalKinds, operation.Kind) {
+				errs = append(errs, fmt.Errorf("%w: %s: %s #%d %s cannot run in a transaction", ErrInvalidTransaction, m.Path, label, i+1, operation.Kind))
+			}
+		}
+	}
+
+	check("operation", m.Operations)
+	check("down operation", m.Down)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,244 @@
+package arangom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMigration_migrateInTransaction(t *testing.T) {
+	ctx := context.Background()
+	tid := driver.TransactionID("trx-1")
+	trxCtx := driver.WithTransactionID(ctx, tid)
+	collections := driver.TransactionCollections{Read: []string{"roles"}, Write: []string{"users"}}
+
+	newMigration := func() *Migration {
+		return &Migration{
+			ID: 123,
+			Operations: []*Operation{
+				{
+					Kind:    OperationKindAQLExecute,
+					Options: map[string]any{"query": "FOR u IN users UPDATE u WITH { active: true } IN users"},
+				},
+				{
+					Kind:       OperationKindDocumentsRemove,
+					Collection: "users",
+					Options:    map[string]any{"keys": []any{"1"}},
+				},
+			},
+			Transaction: &MigrationTransaction{
+				Read:  []string{"roles"},
+				Write: []string{"users"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		db       func() driver.Database
+		wantDone []int
+		wantErr  bool
+	}{
+		{
+			name: "commit transaction",
+			db: func() driver.Database {
+				coll := new(MockArangoCollection)
+				coll.On("RemoveDocuments", trxCtx, []string{"1"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+				db := new(MockArangoDB)
+				db.On("BeginTransaction", ctx, collections, (*driver.BeginTransactionOptions)(nil)).Return(tid, nil)
+				db.On("Query", trxCtx, mock.Anything, mock.Anything).Return(nil, nil)
+				db.On("Collection", trxCtx, "users").Return(coll, nil)
+				db.On("CommitTransaction", ctx, tid, (*driver.CommitTransactionOptions)(nil)).Return(nil)
+
+				return db
+			},
+			wantDone: []int{0, 1},
+		},
+		{
+			name: "abort transaction",
+			db: func() driver.Database {
+				coll := new(MockArangoCollection)
+				coll.On("RemoveDocuments", trxCtx, []string{"1"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{fmt.Errorf("document not found")}, nil)
+
+				db := new(MockArangoDB)
+				db.On("BeginTransaction", ctx, collections, (*driver.BeginTransactionOptions)(nil)).Return(tid, nil)
+				db.On("Query", trxCtx, mock.Anything, mock.Anything).Return(nil, nil)
+				db.On("Collection", trxCtx, "users").Return(coll, nil)
+				db.On("AbortTransaction", ctx, tid, (*driver.AbortTransactionOptions)(nil)).Return(nil)
+
+				return db
+			},
+			wantErr: true,
+		},
+		{
+			name: "abort transaction with error",
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("BeginTransaction", ctx, collections, (*driver.BeginTransactionOptions)(nil)).Return(tid, nil)
+				db.On("Query", trxCtx, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("query error"))
+				db.On("AbortTransaction", ctx, tid, (*driver.AbortTransactionOptions)(nil)).Return(fmt.Errorf("abort error"))
+
+				return db
+			},
+			wantErr: true,
+		},
+		{
+			name: "begin transaction with error",
+			db: func() driver.Database {
+				db := new(MockArangoDB)
+				db.On("BeginTransaction", ctx, collections, (*driver.BeginTransactionOptions)(nil)).Return(driver.TransactionID(""), fmt.Errorf("error"))
+
+				return db
+			},
+			wantErr: true,
+		},
+		{
+			name: "commit transaction with error",
+			db: func() driver.Database {
+				coll := new(MockArangoCollection)
+				coll.On("RemoveDocuments", trxCtx, []string{"1"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+				db := new(MockArangoDB)
+				db.On("BeginTransaction", ctx, collections, (*driver.BeginTransactionOptions)(nil)).Return(tid, nil)
+				db.On("Query", trxCtx, mock.Anything, mock.Anything).Return(nil, nil)
+				db.On("Collection", trxCtx, "users").Return(coll, nil)
+				db.On("CommitTransaction", ctx, tid, (*driver.CommitTransactionOptions)(nil)).Return(fmt.Errorf("error"))
+
+				return db
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := tt.db()
+
+			var done []int
+			err := newMigration().migrate(ctx, db, nil, func(index int) error {
+				done = append(done, index)
+				return nil
+			}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Migration.migrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(done, tt.wantDone) {
+				t.Errorf("Migration.migrate() done = %v, want %v", done, tt.wantDone)
+			}
+
+			db.(*MockArangoDB).AssertExpectations(t)
+		})
+	}
+}
+
+func TestMigration_RollbackInTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tid := driver.TransactionID("trx-1")
+	trxCtx := driver.WithTransactionID(ctx, tid)
+
+	coll := new(MockArangoCollection)
+	coll.On("RemoveDocuments", trxCtx, []string{"admin"}).Return(driver.DocumentMetaSlice{}, driver.ErrorSlice{nil}, nil)
+
+	db := new(MockArangoDB)
+	db.On("BeginTransaction", ctx, driver.TransactionCollections{Exclusive: []string{"roles"}}, (*driver.BeginTransactionOptions)(nil)).Return(tid, nil)
+	db.On("Collection", trxCtx, "roles").Return(coll, nil)
+	db.On("CommitTransaction", ctx, tid, (*driver.CommitTransactionOptions)(nil)).Return(nil)
+
+	migration := &Migration{
+		ID: 123,
+		Operations: []*Operation{
+			{
+				Kind:       OperationKindDocumentsInsert,
+				Collection: "roles",
+				Options: map[string]any{
+					"documents": []any{map[string]any{"_key": "admin"}},
+				},
+			},
+		},
+		Transaction: &MigrationTransaction{Exclusive: []string{"roles"}},
+	}
+
+	if err := migration.Rollback(ctx, db); err != nil {
+		t.Fatalf("Migration.Rollback() error = %v", err)
+	}
+
+	db.AssertExpectations(t)
+	coll.AssertExpectations(t)
+}
+
+func TestMigration_checkTransaction(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/123_activate_users.yaml": {
+			Data: []byte("id: 123\ntransaction:\n  write: [users]\noperations:\n  - kind: executeAQL\n    options:\n      query: RETURN 1\n  - kind: insertDocuments\n    collection: users\n    options:\n      documents: [{_key: admin}]\n"),
+		},
+		"migrations/124_create_posts.yaml": {
+			Data: []byte("id: 124\ntransaction:\n  write: [posts]\noperations:\n  - kind: createCollection\n    collection: posts\n  - kind: executeAQL\n    options:\n      query: RETURN 1\ndown:\n  - kind: deleteCollection\n    collection: posts\n"),
+		},
+		"migrations/125_create_tags.yaml": {
+			Data: []byte("id: 125\noperations:\n  - kind: createCollection\n    collection: tags\n"),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantErrs []string
+	}{
+		{
+			name: "data operations in transaction",
+			path: "migrations/123_activate_users.yaml",
+		},
+		{
+			name: "ddl operations in transaction",
+			path: "migrations/124_create_posts.yaml",
+			wantErrs: []string{
+				"invalid transaction: migrations/124_create_posts.yaml: operation #1 createCollection cannot run in a transaction",
+				"invalid transaction: migrations/124_create_posts.yaml: down operation #1 deleteCollection cannot run in a transaction",
+			},
+		},
+		{
+			name: "ddl operations without transaction",
+			path: "migrations/125_create_tags.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := LoadMigration(fsys, tt.path)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("LoadMigration() error = %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidTransaction) {
+				t.Fatalf("LoadMigration() error = %v, want %v", err, ErrInvalidTransaction)
+			}
+
+			var errs []string
+			for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+				errs = append(errs, err.Error())
+			}
+
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("LoadMigration() errors = %v, want %v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
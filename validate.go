@@ -0,0 +1,221 @@
+package arangom
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrMissingCollection is returned when an operation operating on a
+	// collection, graph or view has no collection set.
+	ErrMissingCollection = fmt.Errorf("missing collection")
+	// ErrMissingOption is returned when an operation has no value for an
+	// option its kind requires.
+	ErrMissingOption = fmt.Errorf("missing option")
+	// ErrInvalidOptions is returned when the options of an operation cannot be
+	// converted to the options of its kind.
+	ErrInvalidOptions = fmt.Errorf("invalid options")
+
+	// collectionlessKinds are the operation kinds not operating on a
+	// collection, graph or view.
+	collectionlessKinds = []OperationKind{
+		OperationKindAQLExecute,
+		OperationKindAQLBackfill,
+		OperationKindAnalyzerCreate,
+		OperationKindAnalyzerDelete,
+		OperationKindGo,
+	}
+
+	// requiredOptions are the options the operation kinds require.
+	requiredOptions = map[OperationKind][]string{
+		OperationKindAQLExecute:            {"query"},
+		OperationKindAQLBackfill:           {"query"},
+		OperationKindCollectionRename:      {"newName"},
+		OperationKindDocumentsImport:       {"file"},
+		OperationKindDocumentsInsert:       {"documents"},
+		OperationKindDocumentsUpsert:       {"documents"},
+		OperationKindDocumentsReplace:      {"documents"},
+		OperationKindDocumentsRemove:       {"keys"},
+		OperationKindFulltextIndexCreate:   {"fields"},
+		OperationKindGeoSpatialIndexCreate: {"fields"},
+		OperationKindHashIndexCreate:       {"fields"},
+		OperationKindPersistentIndexCreate: {"fields"},
+		OperationKindSkipListIndexCreate:   {"fields"},
+		OperationKindTTLIndexCreate:        {"field"},
+		OperationKindZKDIndexCreate:        {"fields"},
+		OperationKindIndexDelete:           {"name"},
+		OperationKindAnalyzerCreate:        {"name"},
+		OperationKindAnalyzerDelete:        {"name"},
+	}
+)
+
+// ValidateMigrations loads the migration files of the root directory like
+// LoadMigrations, without connecting to a database, and reports every problem
+// found instead of stopping at the first one. Besides the problems failing the
+// loading, the operations are checked for a missing collection, missing
+// required options and options that cannot be converted to the options of
+// their kind. The problems are joined into a single error, the ones with a
+// position in a file being SchemaError values. If the migrations are valid,
+// nil is returned.
+func ValidateMigrations(fsys fs.FS, root string, opts ...LoadOption) error {
+	options, err := newLoadOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	files, err := loadMigrationFiles(fsys, root, options)
+	if err != nil {
+		return err
+	}
+
+	problems := make([]error, 0)
+	migrations := make([]*Migration, 0, len(files))
+
+	for _, file := range files {
+		joined, ok := file.err.(interface{ Unwrap() []error })
+		switch {
+		case ok:
+			problems = append(problems, joined.Unwrap()...)
+		case file.err != nil:
+			problems = append(problems, file.err)
+		default:
+			migrations = append(migrations, file.migration)
+		}
+
+		if path.Ext(file.name) == ".aql" {
+			continue
+		}
+
+		b, err := fs.ReadFile(fsys, file.name)
+		if err != nil {
+			continue
+		}
+
+		// The problems of the variables are reported by loading the file.
+		if b, err = interpolate(file.name, b, options.lookupVariable); err != nil {
+			continue
+		}
+
+		problems = append(problems, lintMigrationFile(file.name, b)...)
+	}
+
+	migrations = append(migrations, options.goMigrations()...)
+	problems = append(problems, duplicateMigrations(migrations)...)
+
+	if err := SortMigrations(migrations, options.order); err != nil {
+		problems = append(problems, err)
+	}
+
+	return errors.Join(problems...)
+}
+
+// lintMigrationFile returns the problems of the operations of a YAML or JSON
+// migration file. Operations that cannot be decoded are skipped, as decoding
+// the file reports them.
+func lintMigrationFile(name string, b []byte) []error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+
+	doc := node.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	problems := make([]error, 0)
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		if value.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		label := "operation"
+		switch key.Value {
+		case "operations":
+		case "down":
+			label = "down operation"
+		default:
+			continue
+		}
+
+		for j, operation := range value.Content {
+			problems = append(problems, lintOperationNode(name, fmt.Sprintf("%s #%d", label, j+1), operation)...)
+		}
+	}
+
+	return problems
+}
+
+// lintOperationNode returns the problems of the operation node. The problems
+// are prefixed with the given label identifying the operation. Operations not
+// matching the schema are skipped, as their problems, like a misspelled
+// option, are reported by decoding the file already.
+func lintOperationNode(name string, label string, node *yaml.Node) []error {
+	if len(checkOperationNode(name, node)) > 0 {
+		return nil
+	}
+
+	operation := new(Operation)
+	if err := node.Decode(operation); err != nil {
+		return nil
+	}
+
+	label = fmt.Sprintf("%s %s", label, operation.Kind)
+
+	collectionNode, optionsNode := node, node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "collection":
+			collectionNode = node.Content[i+1]
+		case "options":
+			optionsNode = node.Content[i+1]
+		}
+	}
+
+	problems := make([]error, 0)
+
+	if !slices.Contains(collectionlessKinds, operation.Kind) && strings.TrimSpace(operation.Collection) == "" {
+		problems = append(problems, schemaError(name, collectionNode, fmt.Errorf("%s: %w", label, ErrMissingCollection)))
+	}
+
+	for _, option := range requiredOptions[operation.Kind] {
+		if isEmptyOption(operation.Options[option]) {
+			problems = append(problems, schemaError(name, optionsNode, fmt.Errorf("%s: %w %q", label, ErrMissingOption, option)))
+		}
+	}
+
+	if _, err := operation.ResolveOptions(); err != nil {
+		problems = append(problems, schemaError(name, optionsNode, fmt.Errorf("%s: %w: %s", label, ErrInvalidOptions, err.Error())))
+	}
+
+	return problems
+}
+
+// isEmptyOption returns true if the option value is missing, an empty string
+// or an empty collection.
+func isEmptyOption(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s) == ""
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,198 @@
+package arangom
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidateMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		opts     []LoadOption
+		wantErrs []string
+	}{
+		{
+			name: "validate valid migrations",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml":    {Data: []byte("id: 123\noperations:\n  - kind: createCollection\n    collection: users\n  - kind: createPersistentIndex\n    collection: users\n    options:\n      fields: [email]\n")},
+				"migrations/124_activate_users.aql":   {Data: []byte("// +arangom id: 124\nFOR u IN users UPDATE u WITH { active: true } IN users\n")},
+				"migrations/125_create_analyzer.json": {Data: []byte(`{"id": 125, "operations": [{"kind": "createAnalyzer", "options": {"name": "text_en", "type": "text"}}]}`)},
+			},
+		},
+		{
+			name: "validate missing collection",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\noperations:\n  - kind: createCollection\n  - kind: deleteCollection\n    collection: \"\"\n")},
+			},
+			wantErrs: []string{
+				"migrations/123_create_users.yaml:3:5: operation #1 createCollection: missing collection",
+				"migrations/123_create_users.yaml:5:17: operation #2 deleteCollection: missing collection",
+			},
+		},
+		{
+			name: "validate missing options",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\noperations:\n  - kind: executeAQL\n  - kind: createPersistentIndex\n    collection: users\n    options:\n      fields: []\ndown:\n  - kind: deleteIndex\n    collection: users\n")},
+			},
+			wantErrs: []string{
+				`migrations/123_create_users.yaml:3:5: operation #1 executeAQL: missing option "query"`,
+				`migrations/123_create_users.yaml:7:7: operation #2 createPersistentIndex: missing option "fields"`,
+				`migrations/123_create_users.yaml:9:5: down operation #1 deleteIndex: missing option "name"`,
+			},
+		},
+		{
+			name: "validate collection operations",
+			fsys: fstest.MapFS{
+				"migrations/123_rename_users.yaml": {Data: []byte("id: 123\noperations:\n  - kind: renameCollection\n    collection: users\n  - kind: truncateCollection\n")},
+			},
+			wantErrs: []string{
+				`migrations/123_rename_users.yaml:3:5: operation #1 renameCollection: missing option "newName"`,
+				"migrations/123_rename_users.yaml:5:5: operation #2 truncateCollection: missing collection",
+			},
+		},
+		{
+			name: "validate import documents operations",
+			fsys: fstest.MapFS{
+				"migrations/123_seed_countries.yaml": {Data: []byte("id: 123\noperations:\n  - kind: importDocuments\n    collection: countries\n    options:\n      file: countries.jsonl\n      onDuplicate: merge\n  - kind: importDocuments\n    collection: countries\n")},
+				"migrations/countries.jsonl":         {Data: []byte("{\"_key\": \"hu\"}\n")},
+			},
+			wantErrs: []string{
+				`migrations/123_seed_countries.yaml:6:7: operation #1 importDocuments: invalid options: onDuplicate must be error, update, replace or ignore, got "merge"`,
+				`migrations/123_seed_countries.yaml:8:5: operation #2 importDocuments: missing option "file"`,
+			},
+		},
+		{
+			name: "validate document operations",
+			fsys: fstest.MapFS{
+				"migrations/123_seed_roles.yaml": {Data: []byte("id: 123\noperations:\n  - kind: upsertDocuments\n    collection: roles\n    options:\n      documents:\n        - name: admin\n  - kind: removeDocuments\n    collection: roles\n")},
+			},
+			wantErrs: []string{
+				"migrations/123_seed_roles.yaml:6:7: operation #1 upsertDocuments: invalid options: document #1 has no _key",
+				`migrations/123_seed_roles.yaml:8:5: operation #2 removeDocuments: missing option "keys"`,
+			},
+		},
+		{
+			name: "validate backfill operations",
+			fsys: fstest.MapFS{
+				"migrations/123_activate_users.yaml": {Data: []byte("id: 123\noperations:\n  - kind: backfillAQL\n    collection: users\n    options:\n      query: \"FOR u IN users LIMIT @batch UPDATE u WITH { active: true } IN users RETURN OLD._key\"\n")},
+			},
+			wantErrs: []string{
+				"migrations/123_activate_users.yaml:6:7: operation #1 backfillAQL: invalid options: query must use the @lastKey bind variable",
+			},
+		},
+		{
+			name: "validate invalid options",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\noperations:\n  - kind: createCollection\n    collection: users\n    options:\n      waitForSync: yes please\n")},
+			},
+			wantErrs: []string{
+				"migrations/123_create_users.yaml:6:7: operation #1 createCollection: invalid options: json: cannot unmarshal string into Go struct field CreateCollectionOptions.waitForSync of type bool",
+			},
+		},
+		{
+			name: "validate every file",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml":  {Data: []byte("id: 123\noperations:\n  - kind: createColection\n    collection: users\n  - kind: executeAQL\n")},
+				"migrations/124_create_posts.yaml":  {Data: []byte("id: 124\noperations:\n  - kind: createCollection\n    colection: posts\n")},
+				"migrations/125_activate_users.aql": {Data: []byte("// +arangom StatementEnd\n")},
+			},
+			wantErrs: []string{
+				"migrations/123_create_users.yaml:3:11: invalid operation kind: createColection",
+				`migrations/123_create_users.yaml:5:5: operation #2 executeAQL: missing option "query"`,
+				`migrations/124_create_posts.yaml:4:5: unknown field "colection" in operation`,
+				"invalid AQL migration: migrations/125_activate_users.aql:1: StatementEnd without StatementBegin",
+			},
+		},
+		{
+			name: "validate misspelled option",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\noperations:\n  - kind: createPersistentIndex\n    collection: users\n    options:\n      fileds: [email]\n  - kind: executeAQL\n")},
+			},
+			wantErrs: []string{
+				`migrations/123_create_users.yaml:6:7: unknown field "fileds" in options of createPersistentIndex`,
+				`migrations/123_create_users.yaml:7:5: operation #2 executeAQL: missing option "query"`,
+			},
+		},
+		{
+			name: "validate duplicate IDs",
+			fsys: fstest.MapFS{
+				"migrations/123_create_users.yaml": {Data: []byte("id: 123\n")},
+				"migrations/123_create_posts.yaml": {Data: []byte("id: 123\n")},
+				"migrations/123_create_tags.yaml":  {Data: []byte("id: 123\n")},
+			},
+			wantErrs: []string{
+				"duplicate migration ID: 123: migrations/123_create_posts.yaml and migrations/123_create_tags.yaml",
+				"duplicate migration ID: 123: migrations/123_create_posts.yaml and migrations/123_create_users.yaml",
+			},
+		},
+		{
+			name: "validate order",
+			fsys: fstest.MapFS{
+				"migrations/create_users.yaml": {Data: []byte("id: 123\n")},
+			},
+			opts: []LoadOption{WithOrderStrategy(OrderByVersion)},
+			wantErrs: []string{
+				"invalid migration version: migrations/create_users.yaml has no version prefix",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateMigrations(tt.fsys, "migrations", tt.opts...)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateMigrations() error = %v", err)
+				}
+
+				return
+			}
+
+			var joined interface{ Unwrap() []error }
+			if !errors.As(err, &joined) {
+				t.Fatalf("ValidateMigrations() error = %v, want %v", err, tt.wantErrs)
+			}
+
+			errs := joined.Unwrap()
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("ValidateMigrations() error = %v, want %v", err, tt.wantErrs)
+			}
+
+			for i, err := range errs {
+				if err.Error() != tt.wantErrs[i] {
+					t.Errorf("ValidateMigrations() error #%d = %v, want %v", i, err, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsEmptyOption(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{name: "nil", value: nil, want: true},
+		{name: "empty string", value: " ", want: true},
+		{name: "empty list", value: []any{}, want: true},
+		{name: "empty map", value: map[string]any{}, want: true},
+		{name: "string", value: "idx", want: false},
+		{name: "list", value: []any{"email"}, want: false},
+		{name: "bool", value: false, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isEmptyOption(tt.value); got != tt.want {
+				t.Errorf("isEmptyOption() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}